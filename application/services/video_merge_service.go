@@ -210,7 +210,7 @@ func (s *VideoMergeService) mergeVideoClips(client video.VideoClient, scenes []m
 		VideoURL:  relPath, // 只保存相对路径
 		Duration:  int(totalDuration),
 		Completed: true,
-		Status:    "completed",
+		Status:    video.StatusCompleted,
 	}
 
 	return result, nil
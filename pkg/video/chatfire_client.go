@@ -328,8 +328,9 @@ func (c *ChatfireClient) GenerateVideo(imageURL, prompt string, opts ...VideoOpt
 
 	videoResult := &VideoResult{
 		TaskID:    taskID,
-		Status:    status,
-		Completed: status == "completed" || status == "succeeded",
+		Status:    NormalizeStatus(status),
+		RawStatus: status,
+		Completed: NormalizeStatus(status) == StatusCompleted,
 		Duration:  options.Duration,
 	}
 
@@ -402,8 +403,9 @@ func (c *ChatfireClient) GetTaskStatus(taskID string) (*VideoResult, error) {
 
 	videoResult := &VideoResult{
 		TaskID:    responseTaskID,
-		Status:    status,
-		Completed: status == "completed" || status == "succeeded",
+		Status:    NormalizeStatus(status),
+		RawStatus: status,
+		Completed: NormalizeStatus(status) == StatusCompleted,
 	}
 
 	if errMsg := getErrorMessage(result.Error); errMsg != "" {
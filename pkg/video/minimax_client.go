@@ -194,7 +194,8 @@ func (c *MinimaxClient) GenerateVideo(imageURL, prompt string, opts ...VideoOpti
 	// 第一步只返回 task_id，状态为 Processing
 	videoResult := &VideoResult{
 		TaskID:    result.TaskID,
-		Status:    "Processing",
+		Status:    StatusInProgress,
+		RawStatus: "Processing",
 		Completed: false,
 	}
 
@@ -240,23 +241,22 @@ func (c *MinimaxClient) GetTaskStatus(taskID string) (*VideoResult, error) {
 
 	videoResult := &VideoResult{
 		TaskID:    queryResult.TaskID,
-		Status:    queryResult.Status,
+		Status:    NormalizeStatus(queryResult.Status),
+		RawStatus: queryResult.Status,
 		Width:     queryResult.VideoWidth,
 		Height:    queryResult.VideoHeight,
-		Completed: false,
+		Completed: NormalizeStatus(queryResult.Status) == StatusCompleted,
 	}
 
 	// 如果状态是 Success 且有 file_id，则获取文件下载地址
-	if queryResult.Status == "Success" && queryResult.FileID != "" {
+	if videoResult.Completed && queryResult.FileID != "" {
 		downloadURL, err := c.getFileDownloadURL(queryResult.FileID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get download URL: %w", err)
 		}
 		videoResult.VideoURL = downloadURL
-		videoResult.Completed = true
-	} else if queryResult.Status == "Failed" {
+	} else if videoResult.Status == StatusFailed {
 		videoResult.Error = "Video generation failed"
-		videoResult.Completed = true
 	}
 
 	return videoResult, nil
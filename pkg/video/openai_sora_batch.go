@@ -0,0 +1,137 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// BatchItem is the outcome of one request in a batch. Index is the
+// request's position in the slice passed to BatchGenerate.
+type BatchItem struct {
+	Index   int
+	Request GenerateRequest
+	Result  *VideoResult
+	Err     error
+}
+
+// BatchGenerate submits reqs with at most concurrency requests in flight.
+// Items are returned in input order; a failed request only fills its own
+// Err. Once ctx is done the remaining requests are not submitted and get
+// ctx.Err().
+func (c *OpenAISoraClient) BatchGenerate(ctx context.Context, reqs []GenerateRequest, concurrency int) []BatchItem {
+	items := make([]BatchItem, len(reqs))
+	pending := make([]int, len(reqs))
+	for i, req := range reqs {
+		items[i] = BatchItem{Index: i, Request: req}
+		pending[i] = i
+	}
+
+	c.runBatch(ctx, items, pending, concurrency)
+	return items
+}
+
+// RetryFailed resubmits only the items of a previous BatchGenerate whose Err
+// is set and returns a copy of items with those slots replaced; successful
+// items are kept as they are and never sent again. A request that failed
+// after reaching the provider (a timeout or broken connection) may still
+// have created a task, so give requests an IdempotencyKey to keep such a
+// retry from being charged twice.
+func (c *OpenAISoraClient) RetryFailed(ctx context.Context, items []BatchItem, concurrency int) []BatchItem {
+	retried := slices.Clone(items)
+	var pending []int
+	for i := range retried {
+		if retried[i].Err != nil {
+			retried[i].Result, retried[i].Err = nil, nil
+			pending = append(pending, i)
+		}
+	}
+
+	c.runBatch(ctx, retried, pending, concurrency)
+	return retried
+}
+
+// runBatch submits the requests of items[i] for every i in pending, filling
+// in their Result and Err.
+func (c *OpenAISoraClient) runBatch(ctx context.Context, items []BatchItem, pending []int, concurrency int) {
+	if err := c.inflight.start(); err != nil {
+		for _, i := range pending {
+			items[i].Err = err
+		}
+		return
+	}
+	defer c.inflight.done()
+
+	started := forEachBounded(ctx, len(pending), concurrency, func(k int) {
+		i := pending[k]
+		items[i].Result, items[i].Err = c.GenerateFromRequest(ctx, items[i].Request)
+	})
+	for _, i := range pending[started:] {
+		items[i].Err = ctx.Err()
+	}
+}
+
+// DownloadAll downloads results into dir through DownloadVideoToFile, at
+// most concurrency at a time, so each file is named after its TaskID with
+// the extension matching the response's Content-Type, e.g. video_123.mp4
+// or video_123.webm. Paths and errors are returned in input order; a failed
+// download leaves no file behind. Once ctx is done the remaining downloads are skipped and get
+// ctx.Err().
+func (c *OpenAISoraClient) DownloadAll(ctx context.Context, results []*VideoResult, dir string, concurrency int) ([]string, []error) {
+	paths := make([]string, len(results))
+	errs := make([]error, len(results))
+
+	started := forEachBounded(ctx, len(results), concurrency, func(i int) {
+		path, err := c.DownloadVideoToFile(ctx, results[i], dir)
+		if err != nil {
+			if results[i] != nil && results[i].TaskID != "" {
+				err = fmt.Errorf("download %s: %w", results[i].TaskID, err)
+			}
+			errs[i] = err
+			return
+		}
+		paths[i] = path
+	})
+	for i := started; i < len(results); i++ {
+		errs[i] = ctx.Err()
+	}
+
+	return paths, errs
+}
+
+// forEachBounded calls fn(i) for i from 0 to n-1 in order, at most
+// concurrency calls at a time (one when concurrency <= 0), and waits for
+// them to return. Once ctx is done no further calls are started; it returns
+// how many were, so the callers can mark the rest as skipped.
+func forEachBounded(ctx context.Context, n, concurrency int, fn func(i int)) int {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(concurrency, n); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(i)
+			}
+		}()
+	}
+	defer wg.Wait()
+	defer close(jobs)
+
+	for i := 0; i < n; i++ {
+		if ctx.Err() != nil {
+			return i
+		}
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			return i
+		}
+	}
+	return n
+}
@@ -0,0 +1,282 @@
+package video
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"mime/multipart"
+	"slices"
+	"sync"
+)
+
+// Encoding selects the body format of generate requests.
+type Encoding int
+
+const (
+	// EncodingMultipartForm sends multipart/form-data with reference images
+	// uploaded as file parts, as the OpenAI API expects, except for a
+	// WithInputImageURL URL, which is sent as a plain field.
+	EncodingMultipartForm Encoding = iota
+	// EncodingJSON sends an application/json body. Reference images are
+	// passed as URLs, or as data URIs when they come from local files or
+	// WithFetchAndUpload is used.
+	EncodingJSON
+)
+
+// formField is a single generation parameter.
+type formField struct {
+	name  string
+	value any
+}
+
+// soraFields lists the generation parameters shared by every encoding.
+func soraFields(prompt, model string, options *VideoOptions) ([]formField, error) {
+	fields := []formField{
+		{"model", model},
+		{"prompt", prompt},
+	}
+
+	if options.NegativePrompt != "" {
+		fields = append(fields, formField{"negative_prompt", options.NegativePrompt})
+	}
+
+	if options.Duration > 0 {
+		fields = append(fields, formField{"seconds", fmt.Sprintf("%d", options.Duration)})
+	}
+
+	size, err := soraSize(options, model)
+	if err != nil {
+		return nil, err
+	}
+	if size != "" {
+		fields = append(fields, formField{"size", size})
+	}
+
+	if options.FPS > 0 {
+		fields = append(fields, formField{"fps", options.FPS})
+	}
+
+	if options.Quality != "" {
+		fields = append(fields, formField{"quality", options.Quality})
+	}
+
+	if options.MotionStrength != nil {
+		fields = append(fields, formField{"motion_strength", *options.MotionStrength})
+	}
+
+	if options.Loop != nil {
+		fields = append(fields, formField{"loop", *options.Loop})
+	}
+
+	if options.WebhookURL != "" {
+		fields = append(fields, formField{"callback_url", options.WebhookURL})
+	}
+
+	if options.Seed != 0 {
+		fields = append(fields, formField{"seed", options.Seed})
+	}
+
+	if options.Variations > 1 {
+		fields = append(fields, formField{"n", options.Variations})
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(options.ExtraFields)) {
+		fields = append(fields, formField{key, options.ExtraFields[key]})
+	}
+
+	return fields, nil
+}
+
+// buildBody encodes the generation request according to c.RequestEncoding.
+// It returns a function producing a fresh body for every request attempt,
+// so doRequest can replay it on retry, together with the Content-Type.
+func (c *OpenAISoraClient) buildBody(ctx context.Context, imageURL, prompt, model string, options *VideoOptions) (func() io.Reader, string, error) {
+	if c.RequestEncoding == EncodingJSON {
+		payload, contentType, err := c.buildJSONBody(ctx, imageURL, prompt, model, options)
+		if err != nil {
+			return nil, "", err
+		}
+		return func() io.Reader { return bytes.NewReader(payload) }, contentType, nil
+	}
+	return c.buildMultipartBody(ctx, imageURL, prompt, model, options)
+}
+
+// buildMultipartBody encodes the generation request as multipart/form-data.
+// The body is streamed through a pipe rather than assembled in memory, and
+// local image files are copied from disk as it is read; each attempt reopens
+// them. The request is sent chunked since its length isn't known up front.
+func (c *OpenAISoraClient) buildMultipartBody(ctx context.Context, imageURL, prompt, model string, options *VideoOptions) (func() io.Reader, string, error) {
+	fields, err := soraFields(prompt, model, options)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// The OpenAI Sora API requires 'input_reference' to be a file upload
+	// (binary), not a URL string, so URLs are downloaded and uploaded. Only a
+	// URL given through WithInputImageURL is passed on as a string for the
+	// provider to fetch. Local files take precedence over URLs.
+	paths, urls, err := c.referenceSources(imageURL, model, options)
+	if err != nil {
+		return nil, "", err
+	}
+	var urlField string
+	if options.InputImageURL != "" && !options.FetchAndUpload && len(urls) > 0 && urls[0] == options.InputImageURL {
+		urlField, urls = urls[0], urls[1:]
+	}
+	var images []*referenceImage
+	if options.InputImageReader == nil && len(paths) > 0 {
+		for _, path := range paths {
+			image, err := probeReferenceImage(path)
+			if err != nil {
+				return nil, "", err
+			}
+			images = append(images, image)
+		}
+	} else {
+		images, err = c.loadReferenceImages(ctx, options, paths, urls)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+	name := "input_reference"
+	if urlField != "" && len(images) > 0 || len(images) > 1 {
+		name = "input_reference[]"
+	}
+
+	// The end frame goes in a part of its own
+	var endFrame *referenceImage
+	switch {
+	case options.EndFramePath != "":
+		endFrame, err = probeReferenceImage(options.EndFramePath)
+	case options.EndFrameURL != "":
+		endFrame, err = c.fetchReferenceImage(ctx, options.EndFrameURL)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("end frame: %w", err)
+	}
+
+	// Every attempt must use the boundary announced in the Content-Type
+	boundary := multipart.NewWriter(io.Discard).Boundary()
+	write := func(w io.Writer) error {
+		writer := multipart.NewWriter(w)
+		if err := writer.SetBoundary(boundary); err != nil {
+			return err
+		}
+		for _, field := range fields {
+			if err := writer.WriteField(field.name, fmt.Sprint(field.value)); err != nil {
+				return err
+			}
+		}
+		if urlField != "" {
+			if err := writer.WriteField(name, urlField); err != nil {
+				return err
+			}
+		}
+		for _, image := range images {
+			if err := writeImagePart(writer, name, image); err != nil {
+				return err
+			}
+		}
+		if endFrame != nil {
+			if err := writeImagePart(writer, "end_frame", endFrame); err != nil {
+				return err
+			}
+		}
+		return writer.Close()
+	}
+
+	contentType := "multipart/form-data; boundary=" + boundary
+	return func() io.Reader { return newStreamBody(write) }, contentType, nil
+}
+
+// streamBody is a request body produced by write in a separate goroutine.
+// The goroutine only starts on the first Read, so a body that is never read,
+// like a dry run's, holds no resources, and closing the body stops it.
+type streamBody struct {
+	once  sync.Once
+	pr    *io.PipeReader
+	pw    *io.PipeWriter
+	write func(io.Writer) error
+}
+
+func newStreamBody(write func(io.Writer) error) *streamBody {
+	pr, pw := io.Pipe()
+	return &streamBody{pr: pr, pw: pw, write: write}
+}
+
+func (b *streamBody) Read(p []byte) (int, error) {
+	b.once.Do(func() {
+		go func() {
+			b.pw.CloseWithError(b.write(b.pw))
+		}()
+	})
+	return b.pr.Read(p)
+}
+
+func (b *streamBody) Close() error {
+	return b.pr.Close()
+}
+
+// buildJSONBody encodes the generation request as a JSON object.
+// input_reference is a string for one image and an array for several.
+func (c *OpenAISoraClient) buildJSONBody(ctx context.Context, imageURL, prompt, model string, options *VideoOptions) ([]byte, string, error) {
+	fields, err := soraFields(prompt, model, options)
+	if err != nil {
+		return nil, "", err
+	}
+
+	payload := make(map[string]any, len(fields)+1)
+	for _, field := range fields {
+		payload[field.name] = field.value
+	}
+
+	paths, refs, err := c.referenceSources(imageURL, model, options)
+	if err != nil {
+		return nil, "", err
+	}
+	if options.InputImageReader != nil || len(paths) > 0 || (options.FetchAndUpload && len(refs) > 0) {
+		// Local files have no URL the provider could fetch, so inline them;
+		// the same goes for URLs the caller asked us to fetch
+		images, err := c.loadReferenceImages(ctx, options, paths, refs)
+		if err != nil {
+			return nil, "", err
+		}
+		refs = nil
+		for _, image := range images {
+			refs = append(refs, image.dataURI())
+		}
+	}
+	switch len(refs) {
+	case 0:
+	case 1:
+		payload["input_reference"] = refs[0]
+	default:
+		payload["input_reference"] = refs
+	}
+
+	switch {
+	case options.EndFramePath != "":
+		image, err := openReferenceImage(options.EndFramePath)
+		if err != nil {
+			return nil, "", fmt.Errorf("end frame: %w", err)
+		}
+		payload["end_frame"] = image.dataURI()
+	case options.EndFrameURL != "" && options.FetchAndUpload:
+		image, err := c.fetchReferenceImage(ctx, options.EndFrameURL)
+		if err != nil {
+			return nil, "", fmt.Errorf("end frame: %w", err)
+		}
+		payload["end_frame"] = image.dataURI()
+	case options.EndFrameURL != "":
+		payload["end_frame"] = options.EndFrameURL
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, "", fmt.Errorf("marshal request: %w", err)
+	}
+	return body, "application/json", nil
+}
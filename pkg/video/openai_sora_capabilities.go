@@ -0,0 +1,36 @@
+package video
+
+// Capabilities describes what a Sora model accepts.
+type Capabilities struct {
+	Durations   []int    // supported clip lengths in seconds
+	Resolutions []string // supported "<width>x<height>" sizes
+	ImageInput  bool     // whether input_reference is accepted
+	MaxImages   int      // reference images accepted per request
+	Loop        bool     // whether looping clips can be requested
+	Motion      bool     // whether motion_strength is accepted
+	EndFrame    bool     // whether an end_frame image is accepted
+}
+
+// soraModelCapabilities is the table behind ModelCapabilities.
+var soraModelCapabilities = map[string]Capabilities{
+	"sora-2": {
+		Durations:   []int{4, 8, 12},
+		Resolutions: []string{"1280x720", "720x1280"},
+		ImageInput:  true,
+		MaxImages:   1,
+	},
+	"sora-2-pro": {
+		Durations:   []int{4, 8, 12},
+		Resolutions: []string{"1280x720", "720x1280", "1792x1024", "1024x1792"},
+		ImageInput:  true,
+		MaxImages:   1,
+	},
+}
+
+// ModelCapabilities returns the known capabilities of model. The bool is
+// false for models missing from the table, in which case validation is left
+// to the server.
+func ModelCapabilities(model string) (Capabilities, bool) {
+	caps, ok := soraModelCapabilities[model]
+	return caps, ok
+}
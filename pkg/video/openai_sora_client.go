@@ -1,59 +1,417 @@
 package video
 
 import (
-	"bytes"
-	"encoding/base64"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"mime/multipart"
+	"maps"
 	"net/http"
-	"net/textproto" // Added for explicit MIME header control
-	"path/filepath"
+	"net/url"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/google/uuid"
 )
 
+var _ VideoClient = (*OpenAISoraClient)(nil)
+
+// Version is the version of this library reported in the default User-Agent.
+const Version = "1.0.0"
+
+// DefaultUserAgent is sent with every request unless overridden with
+// WithUserAgent.
+const DefaultUserAgent = "huobao-drama/" + Version
+
+// DryRunTaskID is the TaskID of results returned for WithDryRun calls.
+const DryRunTaskID = "dry-run"
+
+// Doer sends HTTP requests. *http.Client satisfies it.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// TokenSource supplies the bearer token for each request, for gateways that
+// use short-lived OAuth tokens instead of a static API key. Token is called
+// before every attempt, so implementations should cache the token and only
+// refresh it when it is about to expire.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// OpenAISoraClient talks to the OpenAI Sora video API or a compatible
+// gateway.
+//
+// A client is safe for concurrent use by multiple goroutines once it has
+// been configured. Configure it through NewOpenAISoraClient and its options
+// (or by setting fields) before first use and treat every field as read-only
+// afterwards; apart from Close, the methods never modify the client.
 type OpenAISoraClient struct {
-	BaseURL    string
-	APIKey     string
-	Model      string
-	HTTPClient *http.Client
+	BaseURL     string
+	APIKey      string
+	Model       string
+	RetryPolicy RetryPolicy
+
+	// TokenSource, when set, supplies the Authorization token instead of
+	// APIKey.
+	TokenSource TokenSource
+
+	// HTTPClient sends every request. It is an *http.Client by default;
+	// tests can substitute any Doer. WithTimeout and WithTransport only
+	// apply to an *http.Client.
+	HTTPClient Doer
+
+	// StatusHTTPClient, when set, sends the short read-only calls (status
+	// polls, ListTasks and Ping) so they can have a much tighter timeout
+	// than generate uploads; nil means HTTPClient.
+	StatusHTTPClient Doer
+
+	// PollStrategy is used by WaitForCompletion when no fixed interval is
+	// given; the zero value means DefaultPollStrategy.
+	PollStrategy PollStrategy
+
+	// Pricing is used by EstimateCost; nil means DefaultPricing.
+	Pricing PricingTable
+
+	// StallTimeout, when set, makes WaitForCompletion give up with a
+	// StalledError once a task's progress and status have not changed for
+	// that long. Queued tasks can wait a while, so allow for queueing time.
+	StallTimeout time.Duration
+
+	// VideosPath is where the videos resource is mounted below BaseURL,
+	// "/videos" by default. Task endpoints are VideosPath + "/" + taskID.
+	VideosPath string
+
+	// BatchStatusPath, when set, is the endpoint below BaseURL that
+	// GetTaskStatuses POSTs {"ids": [...]} to, answered with
+	// {"data": [task, ...]}. Empty means polling tasks one by one.
+	BatchStatusPath string
+
+	// GenerateTimeout and StatusTimeout, when set, bound a whole
+	// GenerateVideo or GetTaskStatus call on top of the HTTP client's
+	// Timeout.
+	GenerateTimeout time.Duration
+	StatusTimeout   time.Duration
+
+	// Logger, when set, receives one entry per HTTP round trip.
+	Logger Logger
+
+	// Metrics, when set, is told about every HTTP round trip and retry.
+	Metrics MetricsCollector
+
+	// OnRetry, when set, is called before sleeping ahead of each retry with
+	// the retry number (starting at 1), the error that triggered it (an
+	// *APIError or *TransportError) and the delay about to be waited.
+	OnRetry func(attempt int, err error, delay time.Duration)
+
+	// RateLimiter, when set, is waited on before every request attempt.
+	RateLimiter RateLimiter
+
+	// RequestEncoding selects how generate requests are encoded; the zero
+	// value is multipart/form-data.
+	RequestEncoding Encoding
+
+	// MaxReferenceImages caps how many reference images a single request may
+	// attach.
+	MaxReferenceImages int
+
+	// MaxPromptLength caps the prompt and negative prompt, in characters,
+	// so an overlong prompt fails before the request is uploaded. It
+	// defaults to DefaultMaxPromptLength; zero disables the check.
+	MaxPromptLength int
+
+	// Headers are added to every request sent to the API. They are applied
+	// before the client's own headers, so Authorization, Content-Type and
+	// Idempotency-Key are always set by the client. A User-Agent set here
+	// takes precedence over UserAgent.
+	Headers http.Header
+
+	// ResponseMapper, when set, replaces the default parsing of task objects
+	// returned by generate and status calls, for gateways whose schema
+	// differs from OpenAI's (e.g. the URL in "output_url"). It may leave
+	// Status empty and set only RawStatus, which is then normalized.
+	ResponseMapper func(body []byte) (*VideoResult, error)
+
+	// UserAgent is sent with every request, including downloads from other
+	// hosts, unless Headers sets User-Agent for API requests. An empty value
+	// leaves Go's default in place.
+	UserAgent string
+
+	inflight inflight
 }
 
 type OpenAISoraResponse struct {
-	ID          string `json:"id"`
-	Object      string `json:"object"`
-	Model       string `json:"model"`
-	Status      string `json:"status"`
-	Progress    int    `json:"progress"`
-	CreatedAt   int64  `json:"created_at"`
-	CompletedAt int64  `json:"completed_at"`
-	Size        string `json:"size"`
-	Seconds     string `json:"seconds"`
-	Quality     string `json:"quality"`
-	VideoURL    string `json:"video_url"` // 直接的video_url字段
-	Video       struct {
+	ID            string `json:"id"`
+	Object        string `json:"object"`
+	Model         string `json:"model"`
+	Status        string `json:"status"`
+	Progress      int    `json:"progress"`
+	QueuePosition int    `json:"queue_position"` // reported by some gateways while queued
+	CreatedAt     int64  `json:"created_at"`
+	CompletedAt   int64  `json:"completed_at"`
+	Size          string `json:"size"`
+	Seconds       string `json:"seconds"`
+	Quality       string `json:"quality"`
+	VideoURL      string `json:"video_url"`   // 直接的video_url字段
+	PreviewURL    string `json:"preview_url"` // low-res preview some gateways send while rendering
+	Video         struct {
 		URL string `json:"url"`
 	} `json:"video"` // 嵌套的video.url字段（兼容）
 	Error struct {
 		Message string `json:"message"`
 		Type    string `json:"type"`
 	} `json:"error"`
+	Warnings []soraWarning `json:"warnings"`
+}
+
+// soraWarning is a non-fatal notice on a task, given either as a plain string
+// or as an object with a "message".
+type soraWarning string
+
+func (w *soraWarning) UnmarshalJSON(data []byte) error {
+	var message string
+	if err := json.Unmarshal(data, &message); err == nil {
+		*w = soraWarning(message)
+		return nil
+	}
+	var object struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return fmt.Errorf("warning: %w", err)
+	}
+	*w = soraWarning(object.Message)
+	return nil
+}
+
+// UnmarshalJSON accepts "seconds" and "progress" as either JSON strings or
+// numbers, since Sora-compatible gateways disagree on their types.
+func (r *OpenAISoraResponse) UnmarshalJSON(data []byte) error {
+	type alias OpenAISoraResponse
+	aux := struct {
+		*alias
+		Seconds  json.RawMessage `json:"seconds"`
+		Progress json.RawMessage `json:"progress"`
+	}{alias: (*alias)(r)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	seconds, err := rawScalar(aux.Seconds)
+	if err != nil {
+		return fmt.Errorf("seconds: %w", err)
+	}
+	r.Seconds = seconds
+
+	progress, err := rawScalar(aux.Progress)
+	if err != nil {
+		return fmt.Errorf("progress: %w", err)
+	}
+	r.Progress = 0
+	if progress != "" {
+		value, err := strconv.ParseFloat(strings.TrimSuffix(progress, "%"), 64)
+		if err != nil {
+			return fmt.Errorf("progress: invalid value %q", progress)
+		}
+		r.Progress = int(value)
+	}
+
+	return nil
+}
+
+// rawScalar returns the text of a JSON string or number, or "" for null or a
+// missing value.
+func rawScalar(raw json.RawMessage) (string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return "", nil
+	}
+	if raw[0] == '"' {
+		var str string
+		if err := json.Unmarshal(raw, &str); err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(str), nil
+	}
+	var num json.Number
+	if err := json.Unmarshal(raw, &num); err != nil {
+		return "", err
+	}
+	return num.String(), nil
+}
+
+// soraSizes maps a resolution tier and aspect ratio to a Sora size string.
+// Models in ModelCapabilities only accept the entries whose size they
+// support; the rest are for gateways serving other models.
+var soraSizes = map[string]map[string]string{
+	"720p": {
+		"16:9": "1280x720",
+		"9:16": "720x1280",
+		"1:1":  "720x720",
+	},
+	"1080p": {
+		"16:9": "1920x1080",
+		"9:16": "1080x1920",
+		"1:1":  "1080x1080",
+	},
+}
+
+// soraSize returns the size form field for options. An explicit Resolution
+// wins over AspectRatio, which is combined with ResolutionTier (720p by
+// default). For a model listed in ModelCapabilities, a tier and aspect ratio
+// whose size the model doesn't render are rejected here, naming the
+// combinations it does support.
+func soraSize(options *VideoOptions, model string) (string, error) {
+	if options.Resolution != "" {
+		return normalizeSize(options.Resolution), nil
+	}
+	if options.AspectRatio == "" {
+		return "", nil
+	}
+
+	tier := options.ResolutionTier
+	if tier == "" {
+		tier = "720p"
+	}
+	sizes, ok := soraSizes[tier]
+	if !ok {
+		return "", &ValidationError{Field: "resolution tier", Value: tier, Allowed: "720p, 1080p"}
+	}
+	size, ok := sizes[options.AspectRatio]
+	if !ok {
+		return "", &ValidationError{Field: "aspect ratio", Value: options.AspectRatio, Allowed: "16:9, 9:16, 1:1"}
+	}
+
+	if caps, ok := ModelCapabilities(model); ok && !slices.Contains(caps.Resolutions, size) {
+		return "", &ValidationError{
+			Field:   "aspect ratio",
+			Value:   options.AspectRatio + " at " + tier,
+			Allowed: strings.Join(supportedAspectRatios(caps), ", ") + " for " + model,
+		}
+	}
+	return size, nil
 }
 
-func NewOpenAISoraClient(baseURL, apiKey, model string) *OpenAISoraClient {
-	return &OpenAISoraClient{
-		BaseURL: baseURL,
-		APIKey:  apiKey,
-		Model:   model,
+// supportedAspectRatios lists the "<ratio> at <tier>" combinations of
+// soraSizes that caps can render.
+func supportedAspectRatios(caps Capabilities) []string {
+	var combos []string
+	for _, tier := range slices.Sorted(maps.Keys(soraSizes)) {
+		for _, ratio := range slices.Sorted(maps.Keys(soraSizes[tier])) {
+			if slices.Contains(caps.Resolutions, soraSizes[tier][ratio]) {
+				combos = append(combos, ratio+" at "+tier)
+			}
+		}
+	}
+	return combos
+}
+
+func NewOpenAISoraClient(baseURL, apiKey, model string, opts ...SoraClientOption) *OpenAISoraClient {
+	c := &OpenAISoraClient{
+		BaseURL:    normalizeBaseURL(baseURL),
+		VideosPath: "/videos",
+		APIKey:     apiKey,
+		Model:      model,
 		HTTPClient: &http.Client{
 			Timeout: 300 * time.Second,
 		},
+		RetryPolicy:        DefaultRetryPolicy,
+		MaxReferenceImages: 3,
+		MaxPromptLength:    DefaultMaxPromptLength,
+		UserAgent:          DefaultUserAgent,
 	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// NewOpenAISoraClientValidated is like NewOpenAISoraClient but fails fast when
+// baseURL is not an absolute URL or there are no credentials: apiKey may only
+// be empty when a TokenSource is given through WithTokenSource.
+func NewOpenAISoraClientValidated(baseURL, apiKey, model string, opts ...SoraClientOption) (*OpenAISoraClient, error) {
+	normalized := normalizeBaseURL(baseURL)
+	if normalized == "" {
+		return nil, errors.New("sora base URL is required")
+	}
+	u, err := url.Parse(normalized)
+	if err != nil || !u.IsAbs() || u.Host == "" {
+		return nil, fmt.Errorf("sora base URL %q is not a valid absolute URL", baseURL)
+	}
+	c := NewOpenAISoraClient(normalized, apiKey, model, opts...)
+	if c.APIKey == "" && c.TokenSource == nil {
+		return nil, errors.New("sora API key is required")
+	}
+
+	return c, nil
+}
+
+// normalizeBaseURL trims whitespace and trailing slashes so endpoints can be
+// built by plain concatenation.
+func normalizeBaseURL(baseURL string) string {
+	return strings.TrimRight(strings.TrimSpace(baseURL), "/")
 }
 
 func (c *OpenAISoraClient) GenerateVideo(imageURL, prompt string, opts ...VideoOption) (*VideoResult, error) {
+	return c.GenerateVideoContext(context.Background(), imageURL, prompt, opts...)
+}
+
+// GenerateVideoContext is like GenerateVideo but aborts when ctx is cancelled.
+func (c *OpenAISoraClient) GenerateVideoContext(ctx context.Context, imageURL, prompt string, opts ...VideoOption) (*VideoResult, error) {
+	return c.submit(ctx, "generate", c.videosEndpoint(), imageURL, prompt, opts)
+}
+
+// GenerateVideoFromText generates a video from prompt alone, without a
+// reference image.
+func (c *OpenAISoraClient) GenerateVideoFromText(ctx context.Context, prompt string, opts ...VideoOption) (*VideoResult, error) {
+	return c.GenerateVideoContext(ctx, "", prompt, opts...)
+}
+
+// RemixVideo creates a new video that continues or reworks the video of
+// sourceTaskID according to prompt. The returned task is polled like any
+// other.
+func (c *OpenAISoraClient) RemixVideo(ctx context.Context, sourceTaskID, prompt string, opts ...VideoOption) (*VideoResult, error) {
+	if sourceTaskID == "" {
+		return nil, errors.New("source task ID is required")
+	}
+	return c.submit(ctx, "remix", c.videosEndpoint(sourceTaskID, "remix"), "", prompt, opts)
+}
+
+// submit builds the generation form from opts and posts it to endpoint. op
+// labels the call in logs and metrics.
+func (c *OpenAISoraClient) submit(ctx context.Context, op, endpoint, imageURL, prompt string, opts []VideoOption) (*VideoResult, error) {
+	if err := c.inflight.start(); err != nil {
+		return nil, err
+	}
+	defer c.inflight.done()
+
+	return c.submitOne(ctx, op, endpoint, imageURL, prompt, opts)
+}
+
+// submitOne is submit for callers that already registered with inflight.
+func (c *OpenAISoraClient) submitOne(ctx context.Context, op, endpoint, imageURL, prompt string, opts []VideoOption) (*VideoResult, error) {
+	results, err := c.submitAll(ctx, op, endpoint, imageURL, prompt, opts)
+	if len(results) == 0 {
+		return nil, err
+	}
+	return results[0], err
+}
+
+// submitAll sends a generation request and returns every task it created,
+// which is more than one when WithVariations is used. The caller must be
+// registered with inflight.
+func (c *OpenAISoraClient) submitAll(ctx context.Context, op, endpoint, imageURL, prompt string, opts []VideoOption) ([]*VideoResult, error) {
+	if c.GenerateTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.GenerateTimeout)
+		defer cancel()
+	}
+
 	options := &VideoOptions{
 		Duration: 4,
 	}
@@ -61,211 +419,328 @@ func (c *OpenAISoraClient) GenerateVideo(imageURL, prompt string, opts ...VideoO
 	for _, opt := range opts {
 		opt(options)
 	}
+	if options.RequestID != "" {
+		ctx = ContextWithRequestID(ctx, options.RequestID)
+	}
 
 	model := c.Model
 	if options.Model != "" {
 		model = options.Model
 	}
 
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
-
-	// Add basic fields
-	writer.WriteField("model", model)
-	writer.WriteField("prompt", prompt)
+	if err := validateSoraOptions(options, model); err != nil {
+		return nil, err
+	}
+	if err := validatePromptLength(prompt, options.NegativePrompt, c.MaxPromptLength); err != nil {
+		return nil, err
+	}
 
-	if options.Duration > 0 {
-		writer.WriteField("seconds", fmt.Sprintf("%d", options.Duration))
+	newBody, contentType, err := c.buildBody(ctx, imageURL, prompt, model, options)
+	if err != nil {
+		return nil, err
 	}
 
-	if options.Resolution != "" {
-		writer.WriteField("size", options.Resolution)
-	}
-
-	// [PR FIX START]
-	// The OpenAI Sora API requires 'input_reference' to be a file upload (binary), not a URL string
-	// set the Content-Type header (e.g., image/png) or the API returns 400
-	if imageURL != "" {
-		var imageData []byte
-		var mimeType string
-		var filename string = "reference_image.png"
-
-		if strings.HasPrefix(imageURL, "data:") {
-			// Case A: Handle Base64 Data URI (often stored in DB)
-			parts := strings.Split(imageURL, ",")
-			if len(parts) != 2 {
-				return nil, fmt.Errorf("invalid data URI format")
-			}
+	// The same key is sent on every attempt. Only a key passed through
+	// WithIdempotencyKey makes the POST count as safe to repeat, letting
+	// doRequestRetry retry it on 429/5xx and broken connections; the
+	// generated one is sent as a courtesy to providers that dedupe, but not
+	// relied on. Callers retrying on their own should pass a stable key.
+	idempotencyKey := options.IdempotencyKey
+	if idempotencyKey == "" {
+		idempotencyKey = uuid.NewString()
+	}
 
-			// Extract mime type from header (e.g., "data:image/jpeg;base64")
-			header := parts[0]
-			if strings.Contains(header, "image/jpeg") || strings.Contains(header, "image/jpg") {
-				mimeType = "image/jpeg"
-				filename = "reference.jpg"
-			} else if strings.Contains(header, "image/png") {
-				mimeType = "image/png"
-				filename = "reference.png"
-			} else if strings.Contains(header, "image/webp") {
-				mimeType = "image/webp"
-				filename = "reference.webp"
-			} else {
-				mimeType = "image/png" // Default fallback
-			}
+	newReq := func() (*http.Request, error) {
+		req, err := c.newRequest(ctx, "POST", endpoint, newBody())
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+		return req, nil
+	}
 
-			decoded, err := base64.StdEncoding.DecodeString(parts[1])
-			if err != nil {
-				return nil, fmt.Errorf("failed to decode base64 image: %w", err)
-			}
-			imageData = decoded
+	if options.DryRun {
+		req, err := newReq()
+		if err != nil {
+			return nil, fmt.Errorf("create request: %w", err)
+		}
+		return []*VideoResult{{TaskID: DryRunTaskID, DryRunRequest: req}}, nil
+	}
 
-		} else {
-			// Case B: Handle Standard HTTP/HTTPS URL
-			resp, err := http.Get(imageURL)
-			if err != nil {
-				return nil, fmt.Errorf("failed to download reference image: %w", err)
-			}
-			defer resp.Body.Close()
+	resp, respBody, err := c.doRequestRetry(ctx, op, options.IdempotencyKey != "", newReq)
+	if err != nil {
+		return nil, err
+	}
 
-			if resp.StatusCode != http.StatusOK {
-				return nil, fmt.Errorf("failed to download reference image, status: %d", resp.StatusCode)
-			}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, classifyAPIError(newAPIError(resp, respBody))
+	}
 
-			data, err := io.ReadAll(resp.Body)
-			if err != nil {
-				return nil, fmt.Errorf("failed to read downloaded image: %w", err)
-			}
-			imageData = data
-
-			// Use the Content-Type header from the response
-			mimeType = resp.Header.Get("Content-Type")
-
-			// Fallback/Correction if server sends bad headers
-			if mimeType == "" || mimeType == "application/octet-stream" {
-				ext := filepath.Ext(imageURL)
-				switch strings.ToLower(ext) {
-				case ".jpg", ".jpeg":
-					mimeType = "image/jpeg"
-				case ".png":
-					mimeType = "image/png"
-				case ".webp":
-					mimeType = "image/webp"
-				default:
-					mimeType = "image/png"
-				}
-			}
+	results, inlineErr, err := c.decodeVideoResults(resp, respBody)
+	if err != nil {
+		return nil, err
+	}
+	if inlineErr != nil {
+		return results, classifyAPIError(inlineErr)
+	}
 
-			// Ensure filename has extension
-			base := filepath.Base(imageURL)
-			if base != "" && base != "." {
-				if idx := strings.Index(base, "?"); idx != -1 {
-					base = base[:idx]
-				}
-				filename = base
-			}
+	var missingURL bool
+	for _, videoResult := range results {
+		c.logContext(ctx, LogLevelInfo, "sora video task submitted", "task_id", videoResult.TaskID, "status", videoResult.RawStatus, "model", model, "result", videoResult.String())
+		c.logContext(ctx, LogLevelDebug, "sora video prompt", "task_id", videoResult.TaskID, "prompt", prompt)
+		if len(videoResult.Warnings) > 0 {
+			c.logContext(ctx, LogLevelWarn, "sora video task warnings", "task_id", videoResult.TaskID, "warnings", videoResult.Warnings)
 		}
+		missingURL = missingURL || (videoResult.Completed && videoResult.VideoURL == "")
+	}
 
-		// Create the MIME Header manually to force the Content-Type.
-		// Standard writer.CreateFormFile does not set Content-Type, causing "unsupported mimetype" errors.
-		h := make(textproto.MIMEHeader)
-		h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="input_reference"; filename="%s"`, filename))
-		h.Set("Content-Type", mimeType)
-
-		part, err := writer.CreatePart(h)
-		if err != nil {
-			return nil, fmt.Errorf("create part: %w", err)
-		}
-		if _, err := part.Write(imageData); err != nil {
-			return nil, fmt.Errorf("write image data: %w", err)
-		}
+	if missingURL {
+		return results, ErrMissingVideoURL
 	}
-	// [PR FIX END]
 
-	writer.Close()
+	return results, nil
+}
+
+// GetTaskStatus fetches the current state of a task. An invalid, expired or
+// deleted task ID yields an error matching ErrTaskNotFound, so callers can
+// tell it apart from transient failures and stop tracking the task.
+func (c *OpenAISoraClient) GetTaskStatus(taskID string) (*VideoResult, error) {
+	return c.GetTaskStatusContext(context.Background(), taskID)
+}
 
-	endpoint := c.BaseURL + "/videos"
-	req, err := http.NewRequest("POST", endpoint, body)
+// GetTaskStatusContext is like GetTaskStatus but aborts when ctx is cancelled.
+// An empty taskID fails with a ValidationError without sending a request.
+func (c *OpenAISoraClient) GetTaskStatusContext(ctx context.Context, taskID string) (*VideoResult, error) {
+	if err := validateTaskID(taskID); err != nil {
+		return nil, err
+	}
+	if c.StatusTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.StatusTimeout)
+		defer cancel()
+	}
+
+	endpoint := c.videosEndpoint(taskID)
+	resp, body, err := c.doRequest(ctx, "status", func() (*http.Request, error) {
+		return c.newRequest(ctx, "GET", endpoint, nil)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
+		return nil, err
 	}
 
-	req.Header.Set("Content-Type", writer.FormDataContentType())
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		apiErr := newAPIError(resp, body)
+		if isTaskNotFound(apiErr) {
+			return nil, fmt.Errorf("%w: %w", ErrTaskNotFound, apiErr)
+		}
+		return nil, apiErr
+	}
 
-	resp, err := c.HTTPClient.Do(req)
+	videoResult, inlineErr, err := c.decodeVideoResult(resp, body)
 	if err != nil {
-		return nil, fmt.Errorf("send request: %w", err)
+		return nil, err
+	}
+	if inlineErr != nil && isTaskNotFound(inlineErr) {
+		return nil, fmt.Errorf("%w: %w", ErrTaskNotFound, inlineErr)
+	}
+
+	if videoResult.Completed && videoResult.VideoURL == "" {
+		return videoResult, ErrMissingVideoURL
 	}
-	defer resp.Body.Close()
 
-	respBody, err := io.ReadAll(resp.Body)
+	return videoResult, nil
+}
+
+// CreateTask is the first half of the two-phase flow some Sora-compatible
+// gateways use, where POST /videos only creates a draft task and rendering
+// begins with a separate start call. It sends the same request as
+// GenerateVideoContext and returns the draft, typically with StatusDraft;
+// pass its TaskID to StartTask. Providers that start rendering right away
+// only need GenerateVideo.
+func (c *OpenAISoraClient) CreateTask(ctx context.Context, imageURL, prompt string, opts ...VideoOption) (*VideoResult, error) {
+	return c.submit(ctx, "create", c.videosEndpoint(), imageURL, prompt, opts)
+}
+
+// StartTask begins rendering a draft created with CreateTask and returns
+// the task's updated state.
+func (c *OpenAISoraClient) StartTask(ctx context.Context, taskID string) (*VideoResult, error) {
+	if err := validateTaskID(taskID); err != nil {
+		return nil, err
+	}
+	endpoint := c.videosEndpoint(taskID, "start")
+	resp, body, err := c.doRequest(ctx, "start", func() (*http.Request, error) {
+		return c.newRequest(ctx, "POST", endpoint, nil)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("read response: %w", err)
+		return nil, err
 	}
 
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(respBody))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, classifyAPIError(newAPIError(resp, body))
 	}
 
-	var result OpenAISoraResponse
-	if err := json.Unmarshal(respBody, &result); err != nil {
-		return nil, fmt.Errorf("parse response: %w", err)
+	videoResult, inlineErr, err := c.decodeVideoResult(resp, body)
+	if err != nil {
+		return nil, err
+	}
+	if inlineErr != nil {
+		return videoResult, classifyAPIError(inlineErr)
 	}
+	return videoResult, nil
+}
 
-	if result.Error.Message != "" {
-		return nil, fmt.Errorf("openai error: %s", result.Error.Message)
+// CancelTask asks the provider to stop an in-progress generation.
+func (c *OpenAISoraClient) CancelTask(ctx context.Context, taskID string) error {
+	if err := validateTaskID(taskID); err != nil {
+		return err
+	}
+	endpoint := c.videosEndpoint(taskID, "cancel")
+	resp, body, err := c.doRequest(ctx, "cancel", func() (*http.Request, error) {
+		return c.newRequest(ctx, "POST", endpoint, nil)
+	})
+	if err != nil {
+		return err
 	}
 
-	videoResult := &VideoResult{
-		TaskID:    result.ID,
-		Status:    result.Status,
-		Completed: result.Status == "completed",
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return newAPIError(resp, body)
 	}
 
-	// 优先使用video_url字段，兼容video.url嵌套结构
-	if result.VideoURL != "" {
-		videoResult.VideoURL = result.VideoURL
-	} else if result.Video.URL != "" {
-		videoResult.VideoURL = result.Video.URL
+	return nil
+}
+
+// videosEndpoint builds the URL of the videos resource, or of a sub-resource
+// when segments such as a task ID are given.
+func (c *OpenAISoraClient) videosEndpoint(segments ...string) string {
+	path := strings.Trim(c.VideosPath, "/")
+	if path == "" {
+		path = "videos"
 	}
 
-	return videoResult, nil
+	endpoint := c.BaseURL + "/" + path
+	for _, segment := range segments {
+		endpoint += "/" + url.PathEscape(segment)
+	}
+	return endpoint
 }
 
-func (c *OpenAISoraClient) GetTaskStatus(taskID string) (*VideoResult, error) {
-	endpoint := c.BaseURL + "/videos/" + taskID
-	req, err := http.NewRequest("GET", endpoint, nil)
+// newRequest creates a request against the API carrying the custom Headers
+// and the Authorization header, whose token comes from TokenSource when set
+// and APIKey otherwise.
+func (c *OpenAISoraClient) newRequest(ctx context.Context, method, endpoint string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
 	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
+		return nil, err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	for key, values := range c.Headers {
+		req.Header[http.CanonicalHeaderKey(key)] = append([]string(nil), values...)
+	}
+	c.setUserAgent(req)
+	if id := RequestIDFromContext(ctx); id != "" {
+		req.Header.Set("X-Request-Id", id)
+	}
+	token := c.APIKey
+	if c.TokenSource != nil {
+		token, err = c.TokenSource.Token(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("get token: %w", err)
+		}
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
 
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("send request: %w", err)
+	return req, nil
+}
+
+// setUserAgent applies c.UserAgent to req, if set and req has no User-Agent
+// from Headers yet.
+func (c *OpenAISoraClient) setUserAgent(req *http.Request) {
+	if c.UserAgent != "" && req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", c.UserAgent)
 	}
-	defer resp.Body.Close()
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("read response: %w", err)
+// decodeVideoResult parses the task object in a successful response, through
+// ResponseMapper when one is set. inlineErr is non-nil when the default
+// schema reports an error alongside a 2xx status.
+func (c *OpenAISoraClient) decodeVideoResult(resp *http.Response, body []byte) (videoResult *VideoResult, inlineErr *APIError, err error) {
+	if c.ResponseMapper != nil {
+		videoResult, err = c.mapResponse(body)
+		if err != nil {
+			return nil, nil, err
+		}
+	} else {
+		var result OpenAISoraResponse
+		if err := decodeResponse(resp.StatusCode, body, &result); err != nil {
+			return nil, nil, err
+		}
+		videoResult = parseVideoResult(&result)
+		if result.Error.Message != "" {
+			inlineErr = &APIError{
+				StatusCode: resp.StatusCode,
+				Type:       result.Error.Type,
+				Message:    result.Error.Message,
+				RawBody:    body,
+			}
+		}
 	}
 
-	var result OpenAISoraResponse
-	if err := json.Unmarshal(body, &result); err != nil {
-		return nil, fmt.Errorf("parse response: %w", err)
+	videoResult.RawResponse = body
+	videoResult.ResponseMeta = newResponseMeta(resp)
+	return videoResult, inlineErr, nil
+}
+
+// mapResponse parses a task object with ResponseMapper, normalizing the
+// status it reports.
+func (c *OpenAISoraClient) mapResponse(body []byte) (*VideoResult, error) {
+	videoResult, err := c.ResponseMapper(body)
+	if err != nil {
+		return nil, fmt.Errorf("map response: %w", err)
+	}
+	if videoResult == nil {
+		return nil, errors.New("map response: ResponseMapper returned no result")
 	}
+	if videoResult.Status == "" {
+		videoResult.Status = NormalizeStatus(videoResult.RawStatus)
+	}
+	videoResult.Completed = videoResult.Status == StatusCompleted
+	return videoResult, nil
+}
 
+// parseVideoResult maps a Sora task object onto a VideoResult.
+func parseVideoResult(result *OpenAISoraResponse) *VideoResult {
 	videoResult := &VideoResult{
-		TaskID:    result.ID,
-		Status:    result.Status,
-		Completed: result.Status == "completed",
+		TaskID:        result.ID,
+		Status:        NormalizeStatus(result.Status),
+		RawStatus:     result.Status,
+		Model:         result.Model,
+		Completed:     NormalizeStatus(result.Status) == StatusCompleted,
+		Progress:      result.Progress,
+		QueuePosition: result.QueuePosition,
+		PreviewURL:    result.PreviewURL,
+		Size:          result.Size,
+		Seconds:       result.Seconds,
+		Quality:       result.Quality,
+	}
+
+	if result.CreatedAt > 0 {
+		videoResult.CreatedAt = time.Unix(result.CreatedAt, 0)
+	}
+	if result.CompletedAt > 0 {
+		videoResult.CompletedAt = time.Unix(result.CompletedAt, 0)
 	}
 
 	if result.Error.Message != "" {
 		videoResult.Error = result.Error.Message
 	}
 
+	for _, warning := range result.Warnings {
+		if warning != "" {
+			videoResult.Warnings = append(videoResult.Warnings, string(warning))
+		}
+	}
+
 	// 优先使用video_url字段，兼容video.url嵌套结构
 	if result.VideoURL != "" {
 		videoResult.VideoURL = result.VideoURL
@@ -273,5 +748,109 @@ func (c *OpenAISoraClient) GetTaskStatus(taskID string) (*VideoResult, error) {
 		videoResult.VideoURL = result.Video.URL
 	}
 
-	return videoResult, nil
-}
\ No newline at end of file
+	return videoResult
+}
+
+// soraListResponse is the paginated list returned by GET /videos.
+type soraListResponse struct {
+	Data    []OpenAISoraResponse `json:"data"`
+	HasMore bool                 `json:"has_more"`
+	LastID  string               `json:"last_id"`
+}
+
+// ListTasks returns up to limit recent tasks, starting after the task ID
+// given in after (empty for the first page). The returned cursor is passed as
+// after to fetch the next page and is empty when there are no more results.
+func (c *OpenAISoraClient) ListTasks(ctx context.Context, limit int, after string) ([]*VideoResult, string, error) {
+	query := url.Values{}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	if after != "" {
+		query.Set("after", after)
+	}
+
+	endpoint := c.videosEndpoint()
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	resp, body, err := c.doRequest(ctx, "list", func() (*http.Request, error) {
+		return c.newRequest(ctx, "GET", endpoint, nil)
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, "", newAPIError(resp, body)
+	}
+
+	var list soraListResponse
+	if err := decodeResponse(resp.StatusCode, body, &list); err != nil {
+		return nil, "", err
+	}
+
+	meta := newResponseMeta(resp)
+	results := make([]*VideoResult, 0, len(list.Data))
+	for i := range list.Data {
+		videoResult := parseVideoResult(&list.Data[i])
+		videoResult.ResponseMeta = meta
+		results = append(results, videoResult)
+	}
+
+	var next string
+	if list.HasMore {
+		next = list.LastID
+		if next == "" && len(list.Data) > 0 {
+			next = list.Data[len(list.Data)-1].ID
+		}
+	}
+
+	return results, next, nil
+}
+
+// DeleteTask removes a task and its stored video from the provider. A task
+// that is already gone yields an error matching ErrTaskNotFound, which
+// cleanup loops can safely ignore.
+func (c *OpenAISoraClient) DeleteTask(ctx context.Context, taskID string) error {
+	if err := validateTaskID(taskID); err != nil {
+		return err
+	}
+	endpoint := c.videosEndpoint(taskID)
+	resp, body, err := c.doRequest(ctx, "delete", func() (*http.Request, error) {
+		return c.newRequest(ctx, "DELETE", endpoint, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		apiErr := newAPIError(resp, body)
+		if isTaskNotFound(apiErr) {
+			return fmt.Errorf("%w: %w", ErrTaskNotFound, apiErr)
+		}
+		return apiErr
+	}
+
+	return nil
+}
+
+// Ping checks that the endpoint is reachable and the API key is accepted by
+// listing a single task. It returns nil on success, an *APIError for any
+// error status (401 or 403 meaning a bad key), or the network error.
+func (c *OpenAISoraClient) Ping(ctx context.Context) error {
+	endpoint := c.videosEndpoint() + "?limit=1"
+	resp, body, err := c.doRequest(ctx, "ping", func() (*http.Request, error) {
+		return c.newRequest(ctx, "GET", endpoint, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return newAPIError(resp, body)
+	}
+
+	return nil
+}
@@ -0,0 +1,204 @@
+package video
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestSoraServer returns a server that accepts generate requests and
+// reports every task as completed.
+func newTestSoraServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/videos":
+			fmt.Fprint(w, `{"id":"video_123","status":"queued","progress":0}`)
+		case r.Method == "GET" && strings.HasPrefix(r.URL.Path, "/videos/"):
+			id := strings.TrimPrefix(r.URL.Path, "/videos/")
+			fmt.Fprintf(w, `{"id":%q,"status":"completed","progress":100,"video_url":"https://cdn.example.com/%s.mp4"}`, id, id)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestOpenAISoraClientConcurrentUse shares one client across goroutines;
+// run with -race to detect data races.
+func TestOpenAISoraClientConcurrentUse(t *testing.T) {
+	server := newTestSoraServer(t)
+	client := NewOpenAISoraClient(server.URL, "test-key", "sora-2", WithHeader("X-Org-Id", "org"))
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 100)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			result, err := client.GenerateVideo("", fmt.Sprintf("scene %d", i))
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			status, err := client.GetTaskStatus(result.TaskID)
+			if err != nil {
+				errs <- err
+				return
+			}
+			if !status.Completed {
+				errs <- fmt.Errorf("task %s not completed: %s", status.TaskID, status.Status)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestGetTaskStatusesStopsWhenContextDone(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+	client := NewOpenAISoraClient(server.URL, "test-key", "sora-2")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	taskIDs := make([]string, 2*statusConcurrency)
+	for i := range taskIDs {
+		taskIDs[i] = fmt.Sprintf("video_%d", i)
+	}
+
+	results, err := client.GetTaskStatuses(ctx, taskIDs)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("GetTaskStatuses() error = %v, want context.Canceled", err)
+	}
+	if len(results) != len(taskIDs) {
+		t.Errorf("GetTaskStatuses() returned %d results, want %d", len(results), len(taskIDs))
+	}
+	if n := requests.Load(); n != 0 {
+		t.Errorf("server received %d requests, want 0", n)
+	}
+}
+
+func TestWaitForCompletionPollsAgainForMissingVideoURL(t *testing.T) {
+	var gets atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if gets.Add(1) == 1 {
+			fmt.Fprint(w, `{"id":"video_123","status":"completed","progress":100}`)
+			return
+		}
+		fmt.Fprint(w, `{"id":"video_123","status":"completed","progress":100,"video_url":"https://cdn.example.com/video_123.mp4"}`)
+	}))
+	defer server.Close()
+	client := NewOpenAISoraClient(server.URL, "test-key", "sora-2")
+
+	result, err := client.WaitForCompletion(context.Background(), "video_123", time.Millisecond)
+	if err != nil {
+		t.Fatalf("WaitForCompletion() error = %v", err)
+	}
+	if result.VideoURL != "https://cdn.example.com/video_123.mp4" {
+		t.Errorf("VideoURL = %q, want the URL from the second poll", result.VideoURL)
+	}
+	if n := gets.Load(); n != 2 {
+		t.Errorf("server received %d polls, want 2", n)
+	}
+}
+
+func TestRenderStoryboardKeepsTasksWhenCallerGivesUp(t *testing.T) {
+	var cancels atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/cancel"):
+			cancels.Add(1)
+			fmt.Fprint(w, `{"id":"video_123","status":"cancelled"}`)
+		case r.Method == "POST":
+			fmt.Fprint(w, `{"id":"video_123","status":"queued"}`)
+		default:
+			fmt.Fprint(w, `{"id":"video_123","status":"in_progress","progress":10}`)
+		}
+	}))
+	defer server.Close()
+	client := NewOpenAISoraClient(server.URL, "test-key", "sora-2")
+	client.PollStrategy = PollStrategy{Initial: time.Millisecond, Max: time.Millisecond, Multiplier: 1}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	results, err := client.RenderStoryboard(ctx, Storyboard{{Prompt: "scene one"}}, 1)
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("RenderStoryboard() error = %v, want context.DeadlineExceeded", err)
+	}
+	if len(results) != 1 || results[0] == nil || results[0].TaskID != "video_123" {
+		t.Errorf("RenderStoryboard() results = %+v, want the running task", results)
+	}
+	if n := cancels.Load(); n != 0 {
+		t.Errorf("server received %d cancel requests, want 0", n)
+	}
+}
+
+func TestGenerateLongSingleClipNeedsNoFFmpeg(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	server := newTestSoraServer(t)
+	client := NewOpenAISoraClient(server.URL, "test-key", "sora-2")
+	client.PollStrategy = PollStrategy{Initial: time.Millisecond, Max: time.Millisecond, Multiplier: 1}
+
+	results, err := client.GenerateLong(context.Background(), "", "a cat", 4)
+	if err != nil {
+		t.Fatalf("GenerateLong() error = %v", err)
+	}
+	if len(results) != 1 || !results[0].Completed {
+		t.Errorf("GenerateLong() results = %+v, want one completed clip", results)
+	}
+
+	if _, err := client.GenerateLong(context.Background(), "", "a cat", 16); !errors.Is(err, ErrFFmpegUnavailable) {
+		t.Errorf("GenerateLong() of two clips error = %v, want ErrFFmpegUnavailable", err)
+	}
+}
+
+func TestHeadersUserAgentTakesPrecedence(t *testing.T) {
+	var userAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"video_123","status":"queued"}`)
+	}))
+	defer server.Close()
+
+	client := NewOpenAISoraClient(server.URL, "test-key", "sora-2", WithHeader("User-Agent", "custom/1.0"))
+	if _, err := client.GetTaskStatusContext(context.Background(), "video_123"); err != nil {
+		t.Fatalf("GetTaskStatusContext() error = %v", err)
+	}
+	if userAgent != "custom/1.0" {
+		t.Errorf("User-Agent = %q, want the one from Headers", userAgent)
+	}
+
+	client = NewOpenAISoraClient(server.URL, "test-key", "sora-2")
+	if _, err := client.GetTaskStatusContext(context.Background(), "video_123"); err != nil {
+		t.Fatalf("GetTaskStatusContext() error = %v", err)
+	}
+	if userAgent != DefaultUserAgent {
+		t.Errorf("User-Agent = %q, want DefaultUserAgent", userAgent)
+	}
+}
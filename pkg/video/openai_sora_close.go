@@ -0,0 +1,89 @@
+package video
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrClientClosed is returned for work started after Close was called.
+var ErrClientClosed = errors.New("sora client closed")
+
+// inflight counts the client calls in progress so Close can wait for them.
+type inflight struct {
+	mu     sync.Mutex
+	n      int
+	closed bool
+	idle   chan struct{} // closed when n drops to zero, if Close is waiting
+}
+
+// start registers a call, failing once the client is closed.
+func (f *inflight) start() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return ErrClientClosed
+	}
+	f.n++
+	return nil
+}
+
+// checkOpen returns ErrClientClosed once Close has been called. Calls that
+// submit several tasks under one registration check it before each submit
+// after the first.
+func (f *inflight) checkOpen() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return ErrClientClosed
+	}
+	return nil
+}
+
+// done marks a call registered with start as finished.
+func (f *inflight) done() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.n--
+	if f.n == 0 && f.idle != nil {
+		close(f.idle)
+		f.idle = nil
+	}
+}
+
+// Close stops the client from accepting new generations and polls and waits
+// until those in flight (GenerateVideo, WaitForCompletion, BatchGenerate and
+// the helpers built on them) have finished, or ctx is done. Helpers that
+// submit and then wait, such as GenerateAndWait, RenderStoryboard and
+// GenerateLong, count as a single call, so a task they already created is
+// still waited for, but they submit no further scenes or clips and report
+// those as ErrClientClosed. Calls made after Close return ErrClientClosed;
+// GetTaskStatus and downloads keep working.
+//
+// A server would typically call it on SIGTERM with a shutdown deadline:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+//	defer cancel()
+//	if err := client.Close(ctx); err != nil {
+//		log.Printf("gave up waiting for video tasks: %v", err)
+//	}
+func (c *OpenAISoraClient) Close(ctx context.Context) error {
+	c.inflight.mu.Lock()
+	c.inflight.closed = true
+	if c.inflight.n == 0 {
+		c.inflight.mu.Unlock()
+		return nil
+	}
+	if c.inflight.idle == nil {
+		c.inflight.idle = make(chan struct{})
+	}
+	idle := c.inflight.idle
+	c.inflight.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,152 @@
+package video
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestCloseDuringGenerateAndWaitKeepsTask closes the client while the
+// generate POST is in flight. GenerateAndWait must still wait for the task it
+// created, and Close must wait for GenerateAndWait.
+func TestCloseDuringGenerateAndWaitKeepsTask(t *testing.T) {
+	posted := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/videos":
+			close(posted)
+			<-release
+			fmt.Fprint(w, `{"id":"video_123","status":"queued"}`)
+		case r.Method == "GET" && strings.HasPrefix(r.URL.Path, "/videos/"):
+			fmt.Fprint(w, `{"id":"video_123","status":"completed","progress":100,"video_url":"https://cdn.example.com/video_123.mp4"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	client := NewOpenAISoraClient(server.URL, "test-key", "sora-2")
+
+	type outcome struct {
+		result *VideoResult
+		err    error
+	}
+	generated := make(chan outcome, 1)
+	go func() {
+		result, err := client.GenerateAndWait(context.Background(), "", "a cat")
+		generated <- outcome{result, err}
+	}()
+
+	<-posted
+	closed := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		closed <- client.Close(ctx)
+	}()
+
+	// Only answer the POST once Close has started waiting
+	for {
+		client.inflight.mu.Lock()
+		isClosed := client.inflight.closed
+		client.inflight.mu.Unlock()
+		if isClosed {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+
+	got := <-generated
+	if got.err != nil {
+		t.Fatalf("GenerateAndWait() error = %v, want nil", got.err)
+	}
+	if got.result == nil || got.result.TaskID != "video_123" || !got.result.Completed {
+		t.Fatalf("GenerateAndWait() result = %+v, want completed video_123", got.result)
+	}
+	if err := <-closed; err != nil {
+		t.Fatalf("Close() error = %v, want nil", err)
+	}
+
+	if _, err := client.GenerateAndWait(context.Background(), "", "a dog"); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("GenerateAndWait() after Close error = %v, want ErrClientClosed", err)
+	}
+}
+
+// TestCloseDuringRenderStoryboardStopsSubmitting closes the client while the
+// first scene is being submitted. That scene must still finish, and the
+// remaining scenes must fail with ErrClientClosed without being submitted.
+func TestCloseDuringRenderStoryboardStopsSubmitting(t *testing.T) {
+	var posts atomic.Int32
+	posted := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/videos":
+			if posts.Add(1) == 1 {
+				close(posted)
+				<-release
+			}
+			fmt.Fprint(w, `{"id":"video_123","status":"queued"}`)
+		case r.Method == "GET" && strings.HasPrefix(r.URL.Path, "/videos/"):
+			fmt.Fprint(w, `{"id":"video_123","status":"completed","progress":100,"video_url":"https://cdn.example.com/video_123.mp4"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	client := NewOpenAISoraClient(server.URL, "test-key", "sora-2")
+
+	board := Storyboard{{Prompt: "scene one"}, {Prompt: "scene two"}, {Prompt: "scene three"}}
+	type outcome struct {
+		results []*VideoResult
+		err     error
+	}
+	rendered := make(chan outcome, 1)
+	go func() {
+		results, err := client.RenderStoryboard(context.Background(), board, 1)
+		rendered <- outcome{results, err}
+	}()
+
+	<-posted
+	closed := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		closed <- client.Close(ctx)
+	}()
+	for {
+		if client.inflight.checkOpen() != nil {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+
+	got := <-rendered
+	if !errors.Is(got.err, ErrClientClosed) {
+		t.Fatalf("RenderStoryboard() error = %v, want ErrClientClosed", got.err)
+	}
+	if len(got.results) != len(board) || got.results[0] == nil || !got.results[0].Completed {
+		t.Fatalf("RenderStoryboard() results = %+v, want scene 0 completed", got.results)
+	}
+	for i, result := range got.results[1:] {
+		if result != nil {
+			t.Errorf("scene %d result = %+v, want nil", i+1, result)
+		}
+	}
+	if n := posts.Load(); n != 1 {
+		t.Errorf("server received %d POSTs, want 1", n)
+	}
+	if err := <-closed; err != nil {
+		t.Fatalf("Close() error = %v, want nil", err)
+	}
+}
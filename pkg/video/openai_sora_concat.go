@@ -0,0 +1,135 @@
+package video
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ErrFFmpegUnavailable is returned by Concatenate and GenerateLong when
+// ffmpeg or ffprobe is not on PATH.
+var ErrFFmpegUnavailable = errors.New("ffmpeg/ffprobe not found in PATH")
+
+// Concatenate downloads the videos of results in order and joins them into a
+// single MP4 written to out.
+//
+// MP4 files cannot be joined byte for byte, so the clips are stream-copied
+// with ffmpeg's concat demuxer, which is looked up at runtime. Stream copy
+// assumes every clip uses the same codec, resolution and frame rate, which
+// holds for clips from the same model and settings; clips with different
+// dimensions are rejected before ffmpeg runs.
+func (c *OpenAISoraClient) Concatenate(ctx context.Context, results []*VideoResult, out io.Writer) error {
+	if len(results) == 0 {
+		return errors.New("no videos to concatenate")
+	}
+
+	if _, err := exec.LookPath("ffmpeg"); err != nil {
+		return ErrFFmpegUnavailable
+	}
+	if _, err := exec.LookPath("ffprobe"); err != nil {
+		return ErrFFmpegUnavailable
+	}
+
+	tempDir, err := os.MkdirTemp("", "sora-concat-*")
+	if err != nil {
+		return fmt.Errorf("create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	var list strings.Builder
+	var width, height int
+	for i, result := range results {
+		clipPath := filepath.Join(tempDir, fmt.Sprintf("clip_%03d.mp4", i))
+		if err := c.downloadToFile(ctx, result, clipPath); err != nil {
+			return fmt.Errorf("download clip %d: %w", i, err)
+		}
+
+		w, h, err := probeDimensions(ctx, clipPath)
+		if err != nil {
+			return fmt.Errorf("probe clip %d: %w", i, err)
+		}
+		if i == 0 {
+			width, height = w, h
+		} else if w != width || h != height {
+			return fmt.Errorf("clip %d is %dx%d, expected %dx%d like clip 0", i, w, h, width, height)
+		}
+
+		list.WriteString(fmt.Sprintf("file '%s'\n", clipPath))
+	}
+
+	listFile := filepath.Join(tempDir, "filelist.txt")
+	if err := os.WriteFile(listFile, []byte(list.String()), 0644); err != nil {
+		return fmt.Errorf("create file list: %w", err)
+	}
+
+	outputPath := filepath.Join(tempDir, "output.mp4")
+	cmd := exec.CommandContext(ctx, "ffmpeg",
+		"-f", "concat",
+		"-safe", "0",
+		"-i", listFile,
+		"-c", "copy",
+		"-y",
+		outputPath,
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("ffmpeg execution failed: %w, output: %s", err, string(output))
+	}
+
+	f, err := os.Open(outputPath)
+	if err != nil {
+		return fmt.Errorf("open merged video: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(out, f); err != nil {
+		return fmt.Errorf("write merged video: %w", err)
+	}
+	return nil
+}
+
+// downloadToFile downloads result's video to path, removing the partial file
+// if the download fails.
+func (c *OpenAISoraClient) downloadToFile(ctx context.Context, result *VideoResult, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.DownloadVideo(ctx, result, f); err != nil {
+		f.Close()
+		os.Remove(path)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(path)
+		return err
+	}
+	return nil
+}
+
+// probeDimensions returns the width and height of the first video stream.
+func probeDimensions(ctx context.Context, path string) (int, int, error) {
+	cmd := exec.CommandContext(ctx, "ffprobe",
+		"-v", "error",
+		"-select_streams", "v:0",
+		"-show_entries", "stream=width,height",
+		"-of", "csv=p=0",
+		path,
+	)
+
+	output, err := cmd.Output()
+	if err != nil {
+		return 0, 0, fmt.Errorf("ffprobe failed: %w", err)
+	}
+
+	var width, height int
+	if _, err := fmt.Sscanf(strings.TrimSpace(string(output)), "%d,%d", &width, &height); err != nil {
+		return 0, 0, fmt.Errorf("unexpected ffprobe output %q", strings.TrimSpace(string(output)))
+	}
+	return width, height, nil
+}
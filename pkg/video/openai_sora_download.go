@@ -0,0 +1,319 @@
+package video
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// maxErrorBodySize caps how much of an error response is kept in APIError.
+const maxErrorBodySize = 64 * 1024
+
+// downloadProgressStep is how many bytes are copied between progress callbacks.
+const downloadProgressStep = 256 * 1024
+
+// DownloadProgressFunc receives the bytes downloaded so far and the total
+// size, which is -1 when the server does not send Content-Length.
+type DownloadProgressFunc func(downloaded, total int64)
+
+// DownloadVideo streams the video referenced by result into w and returns the
+// number of bytes written. The API key is only sent when the video is hosted
+// on the same host as BaseURL.
+func (c *OpenAISoraClient) DownloadVideo(ctx context.Context, result *VideoResult, w io.Writer) (int64, error) {
+	return c.DownloadVideoWithProgress(ctx, result, w, nil)
+}
+
+// DownloadVideoWithProgress is like DownloadVideo and calls onProgress
+// roughly every 256KB and once more when the download finishes.
+func (c *OpenAISoraClient) DownloadVideoWithProgress(ctx context.Context, result *VideoResult, w io.Writer, onProgress DownloadProgressFunc) (int64, error) {
+	body, total, err := c.GetVideoContent(ctx, result)
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close()
+
+	if onProgress != nil {
+		w = &progressWriter{w: w, total: total, onProgress: onProgress}
+	}
+
+	n, err := io.Copy(w, body)
+	if onProgress != nil {
+		onProgress(n, total)
+	}
+	if err != nil {
+		return n, fmt.Errorf("download video: %w", err)
+	}
+
+	return n, nil
+}
+
+// GetVideoContent opens the video referenced by result for streaming and
+// returns the body with its Content-Length (-1 when unknown). The caller must
+// close the body.
+func (c *OpenAISoraClient) GetVideoContent(ctx context.Context, result *VideoResult) (io.ReadCloser, int64, error) {
+	resp, err := c.openVideo(ctx, result, 0)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		return nil, 0, newAPIError(resp, body)
+	}
+
+	return resp.Body, resp.ContentLength, nil
+}
+
+// videoExtensions maps the Content-Types providers serve videos with to a
+// file extension.
+var videoExtensions = map[string]string{
+	"video/mp4":       ".mp4",
+	"video/webm":      ".webm",
+	"video/quicktime": ".mov",
+}
+
+// videoExtension picks the file extension for a Content-Type header, falling
+// back to .mp4 when it is missing or unknown.
+func videoExtension(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err == nil {
+		if ext, ok := videoExtensions[mediaType]; ok {
+			return ext
+		}
+	}
+	return ".mp4"
+}
+
+// DownloadVideoToFile downloads the video referenced by result into dir,
+// naming the file after its TaskID with the extension matching the
+// response's Content-Type (.mp4, .webm or .mov, .mp4 when unknown). The
+// video is written to a temporary file in dir and renamed into place once
+// complete, so the path it returns never holds a partial file and a failed
+// download leaves nothing behind.
+func (c *OpenAISoraClient) DownloadVideoToFile(ctx context.Context, result *VideoResult, dir string) (string, error) {
+	if result == nil || result.TaskID == "" {
+		return "", errors.New("download: result has no task ID")
+	}
+
+	resp, err := c.openVideo(ctx, result, 0)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		return "", newAPIError(resp, body)
+	}
+
+	// Task IDs come from the provider; keep them from escaping dir
+	name := filepath.Base(result.TaskID)
+	path := filepath.Join(dir, name+videoExtension(resp.Header.Get("Content-Type")))
+
+	// Write next to the target and rename once complete, so path never
+	// holds a partial video
+	f, err := os.CreateTemp(dir, name+".*.part")
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", fmt.Errorf("download video: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	if err := os.Chmod(f.Name(), 0o644); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return path, nil
+}
+
+// DownloadVideoResume continues an interrupted download, writing the bytes
+// from offset onwards to w, typically a partial file opened for appending:
+//
+//	f, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+//	info, _ := f.Stat()
+//	n, err := client.DownloadVideoResume(ctx, result, f, info.Size())
+//
+// When the server ignores the Range header and sends the whole video, the
+// first offset bytes are read and dropped so w still ends up with the
+// complete file. A partial response whose Content-Range doesn't start at
+// offset is treated the same way: the video is fetched again from the
+// start. It returns the number of bytes written to w.
+func (c *OpenAISoraClient) DownloadVideoResume(ctx context.Context, result *VideoResult, w io.Writer, offset int64) (int64, error) {
+	resp, err := c.openVideo(ctx, result, offset)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusPartialContent {
+		if start, ok := contentRangeStart(resp.Header.Get("Content-Range")); !ok || start != offset {
+			// Appending a range that starts elsewhere would corrupt the file
+			c.logContext(ctx, LogLevelWarn, "sora video range mismatch, downloading from the start", "task_id", result.TaskID, "offset", offset, "content_range", resp.Header.Get("Content-Range"))
+			resp.Body.Close()
+			resp, err = c.openVideo(ctx, result, 0)
+			if err != nil {
+				return 0, err
+			}
+			defer resp.Body.Close()
+		}
+	}
+
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+	case http.StatusOK:
+		if offset > 0 {
+			// No range support: start over and skip what we already have
+			if _, err := io.CopyN(io.Discard, resp.Body, offset); err != nil {
+				return 0, fmt.Errorf("download video: %w", err)
+			}
+		}
+	case http.StatusRequestedRangeNotSatisfiable:
+		// Nothing left to fetch when the file is already complete
+		if resp.Header.Get("Content-Range") == fmt.Sprintf("bytes */%d", offset) {
+			return 0, nil
+		}
+		fallthrough
+	default:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		return 0, newAPIError(resp, body)
+	}
+
+	n, err := io.Copy(w, resp.Body)
+	if err != nil {
+		return n, fmt.Errorf("download video: %w", err)
+	}
+	return n, nil
+}
+
+// contentRangeStart returns the first byte position of a Content-Range
+// header such as "bytes 100-199/200".
+func contentRangeStart(value string) (int64, bool) {
+	spec, ok := strings.CutPrefix(value, "bytes ")
+	if !ok {
+		return 0, false
+	}
+	first, _, ok := strings.Cut(spec, "-")
+	if !ok {
+		return 0, false
+	}
+	start, err := strconv.ParseInt(strings.TrimSpace(first), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return start, true
+}
+
+// openVideo sends the GET for the video referenced by result, asking for the
+// bytes from offset onwards when offset is positive.
+func (c *OpenAISoraClient) openVideo(ctx context.Context, result *VideoResult, offset int64) (*http.Response, error) {
+	if result == nil || result.VideoURL == "" {
+		return nil, ErrMissingVideoURL
+	}
+
+	videoURL, err := url.Parse(result.VideoURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse video URL: %w", err)
+	}
+
+	// Credentials and custom headers only go to the API host itself.
+	var req *http.Request
+	if c.isSameHost(videoURL) {
+		req, err = c.newRequest(ctx, "GET", result.VideoURL, nil)
+	} else {
+		req, err = http.NewRequestWithContext(ctx, "GET", result.VideoURL, nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.setUserAgent(req)
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	c.logContext(ctx, LogLevelInfo, "sora video download", "task_id", result.TaskID, "url", RedactURL(result.VideoURL), "offset", offset)
+	resp, err := c.downloadClient().Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("download cancelled: %w", ctx.Err())
+		}
+		return nil, &TransportError{Op: "download", Err: err}
+	}
+	return resp, nil
+}
+
+// downloadClient returns a copy of HTTPClient that follows redirects but
+// drops the Authorization and custom headers as soon as a redirect leaves the
+// API host, e.g. for a signed CDN URL. A Doer other than *http.Client is
+// used as is and must handle redirects itself.
+func (c *OpenAISoraClient) downloadClient() Doer {
+	base, ok := c.HTTPClient.(*http.Client)
+	if !ok {
+		return c.HTTPClient
+	}
+	hc := *base
+	checkRedirect := hc.CheckRedirect
+	hc.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if !c.isSameHost(req.URL) {
+			req.Header.Del("Authorization")
+			for key := range c.Headers {
+				req.Header.Del(key)
+			}
+		}
+		if checkRedirect != nil {
+			return checkRedirect(req, via)
+		}
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		return nil
+	}
+	return &hc
+}
+
+// isSameHost reports whether u points at the same host as the client's BaseURL.
+func (c *OpenAISoraClient) isSameHost(u *url.URL) bool {
+	base, err := url.Parse(c.BaseURL)
+	if err != nil || base.Host == "" {
+		return false
+	}
+	return u.Host == base.Host
+}
+
+// progressWriter reports the number of bytes written every
+// downloadProgressStep bytes.
+type progressWriter struct {
+	w          io.Writer
+	written    int64
+	reported   int64
+	total      int64
+	onProgress DownloadProgressFunc
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.written += int64(n)
+	if p.written-p.reported >= downloadProgressStep {
+		p.reported = p.written
+		p.onProgress(p.written, p.total)
+	}
+	return n, err
+}
@@ -0,0 +1,118 @@
+package video
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestDownloadVideoRedirectDoesNotLeakKey checks that the API key and custom
+// headers are not forwarded when the video URL redirects to another host.
+func TestDownloadVideoRedirectDoesNotLeakKey(t *testing.T) {
+	var cdnAuth, cdnOrg string
+	cdn := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cdnAuth = r.Header.Get("Authorization")
+		cdnOrg = r.Header.Get("X-Org-Id")
+		w.Write([]byte("mp4-data"))
+	}))
+	defer cdn.Close()
+
+	var apiAuth string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiAuth = r.Header.Get("Authorization")
+		http.Redirect(w, r, cdn.URL+"/signed/video.mp4?token=abc", http.StatusFound)
+	}))
+	defer api.Close()
+
+	client := NewOpenAISoraClient(api.URL, "secret-key", "sora-2", WithHeader("X-Org-Id", "org"))
+
+	var buf bytes.Buffer
+	n, err := client.DownloadVideo(context.Background(), &VideoResult{VideoURL: api.URL + "/videos/video_123/content"}, &buf)
+	if err != nil {
+		t.Fatalf("DownloadVideo() error = %v", err)
+	}
+
+	if apiAuth != "Bearer secret-key" {
+		t.Errorf("API host Authorization = %q, want the API key", apiAuth)
+	}
+	if cdnAuth != "" {
+		t.Errorf("CDN host received Authorization %q, want none", cdnAuth)
+	}
+	if cdnOrg != "" {
+		t.Errorf("CDN host received X-Org-Id %q, want none", cdnOrg)
+	}
+	if n != int64(len("mp4-data")) || buf.String() != "mp4-data" {
+		t.Errorf("DownloadVideo() wrote %d bytes %q, want %q", n, buf.String(), "mp4-data")
+	}
+}
+
+func TestDownloadAllNamesFilesLikeDownloadVideoToFile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "video_2") {
+			w.Header().Set("Content-Type", "video/webm")
+		} else {
+			w.Header().Set("Content-Type", "video/mp4")
+		}
+		w.Write([]byte("video-data"))
+	}))
+	defer server.Close()
+	client := NewOpenAISoraClient(server.URL, "test-key", "sora-2")
+
+	dir := t.TempDir()
+	results := []*VideoResult{
+		{TaskID: "video_1", VideoURL: server.URL + "/videos/video_1/content"},
+		{TaskID: "video_2", VideoURL: server.URL + "/videos/video_2/content"},
+	}
+	paths, errs := client.DownloadAll(context.Background(), results, dir, 2)
+
+	for i, want := range []string{"video_1.mp4", "video_2.webm"} {
+		if errs[i] != nil {
+			t.Fatalf("DownloadAll() error %d = %v", i, errs[i])
+		}
+		if paths[i] != filepath.Join(dir, want) {
+			t.Errorf("DownloadAll() path %d = %q, want %q", i, paths[i], filepath.Join(dir, want))
+		}
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("dir holds %d files, want 2 with no leftover temporary files", len(entries))
+	}
+}
+
+func TestDownloadVideoResumeRestartsOnMisalignedRange(t *testing.T) {
+	const data = "0123456789"
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		if r.Header.Get("Range") != "" {
+			// Claims a partial response but starts at the wrong byte
+			w.Header().Set("Content-Range", "bytes 2-9/10")
+			w.WriteHeader(http.StatusPartialContent)
+			w.Write([]byte(data[2:]))
+			return
+		}
+		w.Write([]byte(data))
+	}))
+	defer server.Close()
+	client := NewOpenAISoraClient(server.URL, "test-key", "sora-2")
+
+	var buf bytes.Buffer
+	n, err := client.DownloadVideoResume(context.Background(), &VideoResult{VideoURL: server.URL + "/videos/video_123/content"}, &buf, 4)
+	if err != nil {
+		t.Fatalf("DownloadVideoResume() error = %v", err)
+	}
+	if buf.String() != data[4:] || n != int64(len(data[4:])) {
+		t.Errorf("DownloadVideoResume() wrote %d bytes %q, want %q", n, buf.String(), data[4:])
+	}
+	if len(ranges) != 2 || ranges[0] != "bytes=4-" || ranges[1] != "" {
+		t.Errorf("Range headers = %q, want a ranged request then a full one", ranges)
+	}
+}
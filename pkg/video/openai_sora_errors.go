@@ -0,0 +1,207 @@
+package video
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+)
+
+// ErrTaskNotFound is returned when the provider reports that a task does not
+// exist, e.g. because it was already deleted, expired or the ID is invalid.
+// It wraps the *APIError, so the provider's message is still available.
+var ErrTaskNotFound = errors.New("video task not found")
+
+// ErrMissingVideoURL is returned together with the VideoResult when a task
+// reports completion but carries no video URL. Polling again usually helps.
+var ErrMissingVideoURL = errors.New("video task completed without a video URL")
+
+// APIError describes an error response returned by the Sora API.
+type APIError struct {
+	StatusCode int
+	Type       string
+	Message    string
+	RawBody    []byte
+
+	// ResponseMeta holds the rate-limit headers of the failed response,
+	// notably Retry-After on a 429.
+	ResponseMeta *ResponseMeta
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("API error (status %d): %s", e.StatusCode, string(e.RawBody))
+	}
+	if e.Type != "" {
+		return fmt.Sprintf("API error (status %d, type %s): %s", e.StatusCode, e.Type, e.Message)
+	}
+	return fmt.Sprintf("API error (status %d): %s", e.StatusCode, e.Message)
+}
+
+// newAPIError builds an APIError from a response, extracting the OpenAI style
+// {"error": {"message", "type"}} payload when present.
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode:   resp.StatusCode,
+		RawBody:      body,
+		ResponseMeta: newResponseMeta(resp),
+	}
+
+	var payload struct {
+		Error struct {
+			Message string `json:"message"`
+			Type    string `json:"type"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil {
+		apiErr.Type = payload.Error.Type
+		apiErr.Message = payload.Error.Message
+	}
+
+	return apiErr
+}
+
+// maxBodySnippet caps how much of an unparseable response is quoted in the
+// error.
+const maxBodySnippet = 256
+
+// decodeResponse unmarshals a successful response body into v. A misbehaving
+// proxy may answer 200 with an empty body or an HTML page, so the error names
+// the status and quotes the start of the body instead of only reporting
+// "invalid character '<'".
+func decodeResponse(statusCode int, body []byte, v any) error {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return fmt.Errorf("parse response: empty body (status %d)", statusCode)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		snippet := body
+		if len(snippet) > maxBodySnippet {
+			snippet = snippet[:maxBodySnippet]
+		}
+		return fmt.Errorf("parse response (status %d, body %q): %w", statusCode, snippet, err)
+	}
+	return nil
+}
+
+// TransportError is returned when a request fails below HTTP: DNS lookup,
+// connecting, TLS, or reading the response body after the status arrived.
+// Err is the underlying error, usually a *url.Error wrapping a net.Error.
+type TransportError struct {
+	Op  string
+	Err error
+}
+
+func (e *TransportError) Error() string {
+	return fmt.Sprintf("send %s request: %v", e.Op, e.Err)
+}
+
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
+
+// Temporary reports whether the failure is likely to go away on retry:
+// timeouts, temporary DNS failures, refused or reset connections and
+// connections closed mid-request. Certificate errors and unknown hosts are
+// not temporary.
+func (e *TransportError) Temporary() bool {
+	var dnsErr *net.DNSError
+	if errors.As(e.Err, &dnsErr) {
+		return dnsErr.IsTemporary || dnsErr.IsTimeout
+	}
+	var netErr net.Error
+	if errors.As(e.Err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(e.Err, syscall.ECONNREFUSED) ||
+		errors.Is(e.Err, syscall.ECONNRESET) ||
+		errors.Is(e.Err, io.EOF) ||
+		errors.Is(e.Err, io.ErrUnexpectedEOF)
+}
+
+// ValidationError is returned before any request is sent when an option has
+// an invalid value.
+type ValidationError struct {
+	Field   string
+	Value   string
+	Allowed string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s %q (allowed: %s)", e.Field, e.Value, e.Allowed)
+}
+
+// notFoundErrorTypes lists the error types gateways use for unknown task
+// IDs when they don't answer with a plain 404.
+var notFoundErrorTypes = map[string]bool{
+	"not_found":          true,
+	"task_not_found":     true,
+	"resource_not_found": true,
+}
+
+// isTaskNotFound reports whether apiErr says the task doesn't exist.
+func isTaskNotFound(apiErr *APIError) bool {
+	return apiErr.StatusCode == http.StatusNotFound || notFoundErrorTypes[apiErr.Type]
+}
+
+// moderationErrorTypes lists the error types providers use for prompts or
+// images rejected by safety filters.
+var moderationErrorTypes = map[string]bool{
+	"content_policy_violation": true,
+	"moderation_blocked":       true,
+	"content_filter":           true,
+}
+
+// quotaErrorType is the error type returned when the account is out of
+// credits.
+const quotaErrorType = "insufficient_quota"
+
+// ModerationError is returned when the provider rejects a request on content
+// policy grounds. Retrying won't help; the prompt or image has to change.
+// Type holds the provider's error type naming the rule that fired.
+type ModerationError struct {
+	*APIError
+}
+
+func (e *ModerationError) Error() string {
+	return fmt.Sprintf("content rejected by moderation (%s): %s", e.Type, e.Message)
+}
+
+func (e *ModerationError) Unwrap() error {
+	return e.APIError
+}
+
+// QuotaExceededError is returned when the account has run out of credits.
+// It usually arrives as a 429 but is never retried: nothing will succeed
+// until billing is sorted out, so callers should stop submitting work.
+type QuotaExceededError struct {
+	*APIError
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("quota exceeded: %s", e.Message)
+}
+
+func (e *QuotaExceededError) Unwrap() error {
+	return e.APIError
+}
+
+// classifyAPIError wraps apiErr in a more specific error type when its Type
+// is recognised:
+//
+//	content_policy_violation, moderation_blocked, content_filter -> *ModerationError
+//	insufficient_quota                                           -> *QuotaExceededError
+//
+// Any other type is returned as the plain *APIError.
+func classifyAPIError(apiErr *APIError) error {
+	switch {
+	case moderationErrorTypes[apiErr.Type]:
+		return &ModerationError{APIError: apiErr}
+	case apiErr.Type == quotaErrorType:
+		return &QuotaExceededError{APIError: apiErr}
+	}
+	return apiErr
+}
@@ -0,0 +1,386 @@
+package video
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// referenceImage is an image attached to a Sora request as a file part.
+// When path is set the data is not held in memory and is copied from disk
+// while the request body is written.
+type referenceImage struct {
+	data     []byte
+	path     string
+	mimeType string
+	filename string
+}
+
+// referenceSources collects the reference image paths and URLs requested
+// through imageURL and options, checks them against MaxReferenceImages and
+// trims them to what the model accepts. Local files win over URLs, so urls
+// is nil whenever a file or reader is set.
+func (c *OpenAISoraClient) referenceSources(imageURL, model string, options *VideoOptions) (paths, urls []string, err error) {
+	if options.InputImagePath != "" {
+		paths = append(paths, options.InputImagePath)
+	}
+	paths = append(paths, options.InputImagePaths...)
+	if options.InputImageReader == nil && len(paths) == 0 {
+		if options.InputImageURL != "" {
+			urls = append(urls, options.InputImageURL)
+		}
+		if imageURL != "" {
+			urls = append(urls, imageURL)
+		}
+		urls = append(urls, options.InputImageURLs...)
+	}
+
+	count := len(paths) + len(urls)
+	if options.InputImageReader != nil {
+		count = 1
+		paths = nil
+	}
+	if c.MaxReferenceImages > 0 && count > c.MaxReferenceImages {
+		return nil, nil, &ValidationError{
+			Field:   "reference images",
+			Value:   strconv.Itoa(count),
+			Allowed: fmt.Sprintf("at most %d", c.MaxReferenceImages),
+		}
+	}
+
+	if caps, ok := ModelCapabilities(model); ok && caps.MaxImages > 0 && count > caps.MaxImages {
+		c.log(LogLevelWarn, "model accepts fewer reference images, extra images dropped", "model", model, "supplied", count, "used", caps.MaxImages)
+		if len(paths) > caps.MaxImages {
+			paths = paths[:caps.MaxImages]
+		}
+		if len(urls) > caps.MaxImages {
+			urls = urls[:caps.MaxImages]
+		}
+	}
+
+	return paths, urls, nil
+}
+
+// loadReferenceImages loads the reference images selected by
+// referenceSources.
+func (c *OpenAISoraClient) loadReferenceImages(ctx context.Context, options *VideoOptions, paths, urls []string) ([]*referenceImage, error) {
+	var images []*referenceImage
+	switch {
+	case options.InputImageReader != nil:
+		image, err := readReferenceImage(options.InputImageReader, options.InputImageName)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, image)
+	case len(paths) > 0:
+		for _, path := range paths {
+			image, err := openReferenceImage(path)
+			if err != nil {
+				return nil, err
+			}
+			images = append(images, image)
+		}
+	default:
+		for _, u := range urls {
+			image, err := c.fetchReferenceImage(ctx, u)
+			if err != nil {
+				return nil, err
+			}
+			images = append(images, image)
+		}
+	}
+
+	return images, nil
+}
+
+// fetchReferenceImage resolves imageURL, which may be a base64 data URI or an
+// HTTP(S) URL, into raw image bytes.
+func (c *OpenAISoraClient) fetchReferenceImage(ctx context.Context, imageURL string) (*referenceImage, error) {
+	image := &referenceImage{filename: "reference_image.png"}
+
+	if strings.HasPrefix(imageURL, "data:") {
+		// Case A: Handle Base64 Data URI (often stored in DB)
+		parts := strings.Split(imageURL, ",")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid data URI format")
+		}
+
+		// Extract mime type from header (e.g., "data:image/jpeg;base64")
+		header := parts[0]
+		if strings.Contains(header, "image/jpeg") || strings.Contains(header, "image/jpg") {
+			image.mimeType = "image/jpeg"
+			image.filename = "reference.jpg"
+		} else if strings.Contains(header, "image/png") {
+			image.mimeType = "image/png"
+			image.filename = "reference.png"
+		} else if strings.Contains(header, "image/webp") {
+			image.mimeType = "image/webp"
+			image.filename = "reference.webp"
+		} else {
+			image.mimeType = "image/png" // Default fallback
+		}
+
+		decoded, err := base64.StdEncoding.DecodeString(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode base64 image: %w", err)
+		}
+		image.data = decoded
+		return image, nil
+	}
+
+	// Case B: Handle Standard HTTP/HTTPS URL
+	req, err := http.NewRequestWithContext(ctx, "GET", imageURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create image request: %w", err)
+	}
+	c.setUserAgent(req)
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("download reference image cancelled: %w", ctx.Err())
+		}
+		return nil, fmt.Errorf("failed to download reference image: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download reference image, status: %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read downloaded image: %w", err)
+	}
+	image.data = data
+
+	// Use the Content-Type header from the response, correcting it if the
+	// server sends a bad one
+	image.mimeType = resp.Header.Get("Content-Type")
+	if image.mimeType == "" || image.mimeType == "application/octet-stream" {
+		image.mimeType = imageMimeType(imageURL, nil)
+	}
+
+	// Ensure filename has extension
+	base := filepath.Base(imageURL)
+	if base != "" && base != "." {
+		if idx := strings.Index(base, "?"); idx != -1 {
+			base = base[:idx]
+		}
+		image.filename = base
+	}
+
+	return image, nil
+}
+
+// openReferenceImage reads a local image file.
+func openReferenceImage(path string) (*referenceImage, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open input image: %w", err)
+	}
+	defer f.Close()
+
+	return readReferenceImage(f, filepath.Base(path))
+}
+
+// probeReferenceImage describes a local image file for streaming, reading
+// only enough of it to sniff the content type.
+func probeReferenceImage(path string) (*referenceImage, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open input image: %w", err)
+	}
+	defer f.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, fmt.Errorf("read input image: %w", err)
+	}
+
+	filename := filepath.Base(path)
+	return &referenceImage{
+		path:     path,
+		mimeType: imageMimeType(filename, head[:n]),
+		filename: filename,
+	}, nil
+}
+
+// readReferenceImage reads an image from r, inferring its content type from
+// filename or, failing that, from the data itself.
+func readReferenceImage(r io.Reader, filename string) (*referenceImage, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read input image: %w", err)
+	}
+
+	if filename == "" {
+		filename = "reference_image.png"
+	}
+
+	return &referenceImage{
+		data:     data,
+		mimeType: imageMimeType(filename, data),
+		filename: filename,
+	}, nil
+}
+
+// imageMimeType guesses an image content type from the name's extension,
+// sniffing data when the extension is unknown.
+func imageMimeType(name string, data []byte) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".jpg", ".jpeg":
+		return "image/jpeg"
+	case ".png":
+		return "image/png"
+	case ".webp":
+		return "image/webp"
+	}
+
+	if len(data) > 0 {
+		if sniffed := http.DetectContentType(data); strings.HasPrefix(sniffed, "image/") {
+			return sniffed
+		}
+	}
+	return "image/png"
+}
+
+// dataURI encodes the image as a base64 data URI.
+func (image *referenceImage) dataURI() string {
+	return "data:" + image.mimeType + ";base64," + base64.StdEncoding.EncodeToString(image.data)
+}
+
+// writeImagePart adds image to writer as a file part named field.
+func writeImagePart(writer *multipart.Writer, field string, image *referenceImage) error {
+	// Create the MIME Header manually to force the Content-Type.
+	// Standard writer.CreateFormFile does not set Content-Type, causing "unsupported mimetype" errors.
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, field, image.filename))
+	h.Set("Content-Type", image.mimeType)
+
+	part, err := writer.CreatePart(h)
+	if err != nil {
+		return fmt.Errorf("create part: %w", err)
+	}
+	if image.path == "" {
+		if _, err := part.Write(image.data); err != nil {
+			return fmt.Errorf("write image data: %w", err)
+		}
+		return nil
+	}
+
+	f, err := os.Open(image.path)
+	if err != nil {
+		return fmt.Errorf("open input image: %w", err)
+	}
+	defer f.Close()
+	if _, err := io.Copy(part, f); err != nil {
+		return fmt.Errorf("write image data: %w", err)
+	}
+	return nil
+}
+
+const (
+	// maxReferenceImageBytes and maxReferenceImageSide are the limits
+	// ValidateReferenceImage checks against.
+	maxReferenceImageBytes = 20 << 20
+	maxReferenceImageSide  = 4096
+)
+
+// ValidateReferenceImage reads the image from r and checks that it is a
+// JPEG, PNG or WebP whose header decodes, of at most 20 MB and 4096 pixels
+// on either side. Failures are reported as a *ValidationError, so a corrupt
+// or unsupported upload can be rejected before GenerateVideo sends it.
+func ValidateReferenceImage(r io.Reader) error {
+	data, err := io.ReadAll(io.LimitReader(r, maxReferenceImageBytes+1))
+	if err != nil {
+		return fmt.Errorf("read reference image: %w", err)
+	}
+	if len(data) > maxReferenceImageBytes {
+		return &ValidationError{
+			Field:   "reference image size",
+			Value:   fmt.Sprintf("more than %d bytes", maxReferenceImageBytes),
+			Allowed: "up to 20 MB",
+		}
+	}
+
+	mimeType := http.DetectContentType(data)
+	var config image.Config
+	switch mimeType {
+	case "image/jpeg", "image/png":
+		config, _, err = image.DecodeConfig(bytes.NewReader(data))
+	case "image/webp":
+		config, err = webpConfig(data)
+	default:
+		return &ValidationError{
+			Field:   "reference image type",
+			Value:   mimeType,
+			Allowed: "image/jpeg, image/png, image/webp",
+		}
+	}
+	if err != nil {
+		return &ValidationError{
+			Field:   "reference image",
+			Value:   "corrupt " + mimeType,
+			Allowed: "a decodable JPEG, PNG or WebP",
+		}
+	}
+
+	if config.Width < 1 || config.Height < 1 || config.Width > maxReferenceImageSide || config.Height > maxReferenceImageSide {
+		return &ValidationError{
+			Field:   "reference image dimensions",
+			Value:   fmt.Sprintf("%dx%d", config.Width, config.Height),
+			Allowed: fmt.Sprintf("up to %dx%d", maxReferenceImageSide, maxReferenceImageSide),
+		}
+	}
+	return nil
+}
+
+// webpConfig reads the canvas size from a WebP header. The standard library
+// has no WebP decoder, and the size is all ValidateReferenceImage needs.
+func webpConfig(data []byte) (image.Config, error) {
+	if len(data) < 30 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return image.Config{}, errors.New("webp: invalid header")
+	}
+
+	var width, height int
+	switch string(data[12:16]) {
+	case "VP8 ":
+		// Lossy: 3 byte frame tag and start code, then 14 bit dimensions
+		if !bytes.Equal(data[23:26], []byte{0x9d, 0x01, 0x2a}) {
+			return image.Config{}, errors.New("webp: invalid VP8 start code")
+		}
+		width = int(binary.LittleEndian.Uint16(data[26:28]) & 0x3fff)
+		height = int(binary.LittleEndian.Uint16(data[28:30]) & 0x3fff)
+	case "VP8L":
+		// Lossless: signature byte, then 14 bit width-1 and height-1
+		if data[20] != 0x2f {
+			return image.Config{}, errors.New("webp: invalid VP8L signature")
+		}
+		bits := binary.LittleEndian.Uint32(data[21:25])
+		width = int(bits&0x3fff) + 1
+		height = int(bits>>14&0x3fff) + 1
+	case "VP8X":
+		// Extended: flags, then 24 bit canvas width-1 and height-1
+		width = int(uint32(data[24])|uint32(data[25])<<8|uint32(data[26])<<16) + 1
+		height = int(uint32(data[27])|uint32(data[28])<<8|uint32(data[29])<<16) + 1
+	default:
+		return image.Config{}, errors.New("webp: unknown chunk")
+	}
+
+	return image.Config{Width: width, Height: height}, nil
+}
@@ -0,0 +1,77 @@
+package video
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Log levels passed to Logger.Log.
+const (
+	LogLevelDebug = "debug"
+	LogLevelInfo  = "info"
+	LogLevelWarn  = "warn"
+	LogLevelError = "error"
+)
+
+// Logger receives structured log events from the Sora client. kv holds
+// alternating keys and values. The client never logs the API key, and
+// prompts are never logged above debug level.
+type Logger interface {
+	Log(level, msg string, kv ...any)
+}
+
+// SlogLogger adapts a *slog.Logger to Logger.
+func SlogLogger(l *slog.Logger) Logger {
+	return slogLogger{l}
+}
+
+type slogLogger struct {
+	l *slog.Logger
+}
+
+func (s slogLogger) Log(level, msg string, kv ...any) {
+	var lvl slog.Level
+	switch level {
+	case LogLevelDebug:
+		lvl = slog.LevelDebug
+	case LogLevelWarn:
+		lvl = slog.LevelWarn
+	case LogLevelError:
+		lvl = slog.LevelError
+	default:
+		lvl = slog.LevelInfo
+	}
+	s.l.Log(context.Background(), lvl, msg, kv...)
+}
+
+// log forwards to c.Logger when one is configured.
+func (c *OpenAISoraClient) log(level, msg string, kv ...any) {
+	if c.Logger == nil {
+		return
+	}
+	c.Logger.Log(level, msg, kv...)
+}
+
+// logContext is like log and adds the request ID carried by ctx, if any.
+func (c *OpenAISoraClient) logContext(ctx context.Context, level, msg string, kv ...any) {
+	if id := RequestIDFromContext(ctx); id != "" {
+		kv = append(kv, "request_id", id)
+	}
+	c.log(level, msg, kv...)
+}
+
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a copy of ctx carrying a correlation ID. Every
+// client call made with it logs the ID as "request_id" and sends it in the
+// X-Request-Id header on each attempt.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the ID set by ContextWithRequestID or
+// WithRequestID, or "".
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
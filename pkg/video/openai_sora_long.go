@@ -0,0 +1,170 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+)
+
+// GenerateLong renders a scene longer than the model allows in one request.
+// totalSeconds is split into clips of lengths the model supports (see
+// ModelCapabilities), rounding the last one up when no supported length fits
+// exactly. Each clip after the first is started from the final frame of the
+// previous one, which is extracted with ffmpeg; ffmpeg is therefore only
+// required when more than one clip is needed. The completed clips are
+// returned in order, ready for Concatenate; on error the clips so far are
+// returned with it, the last one unfinished when its wait failed, so its
+// task can still be polled or cancelled. Close waits for the clip in
+// progress, after which no further clip is submitted and ErrClientClosed
+// is returned.
+//
+// Chaining through a single frame keeps the composition but not the motion,
+// so expect visible seams: a change of camera speed or direction, a small
+// colour or lighting shift, and characters that drift in detail between
+// clips. Prompts describing continuous action hide the seams best.
+func (c *OpenAISoraClient) GenerateLong(ctx context.Context, imageURL, prompt string, totalSeconds int, opts ...VideoOption) ([]*VideoResult, error) {
+	if err := c.inflight.start(); err != nil {
+		return nil, err
+	}
+	defer c.inflight.done()
+
+	options := &VideoOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	model := c.Model
+	if options.Model != "" {
+		model = options.Model
+	}
+
+	var durations []int
+	if caps, ok := ModelCapabilities(model); ok {
+		durations = caps.Durations
+	}
+	clips := splitDuration(totalSeconds, durations)
+	if len(clips) == 0 {
+		return nil, &ValidationError{
+			Field:   "duration",
+			Value:   fmt.Sprintf("%d", totalSeconds),
+			Allowed: "a positive number of seconds",
+		}
+	}
+	if len(clips) > 1 {
+		// Only chaining clips needs ffmpeg, to extract the last frames
+		if _, err := exec.LookPath("ffmpeg"); err != nil {
+			return nil, ErrFFmpegUnavailable
+		}
+	}
+
+	tempDir, err := os.MkdirTemp("", "sora-long-*")
+	if err != nil {
+		return nil, fmt.Errorf("create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	results := make([]*VideoResult, 0, len(clips))
+	var lastFrame string
+	for i, seconds := range clips {
+		clipOpts := append(slices.Clone(opts), WithDuration(seconds))
+		if options.IdempotencyKey != "" {
+			// One key for every clip would collapse them into one task
+			clipOpts = append(clipOpts, WithIdempotencyKey(fmt.Sprintf("%s-%d", options.IdempotencyKey, i)))
+		}
+		if lastFrame != "" {
+			clipOpts = append(clipOpts, withOnlyInputImageFile(lastFrame))
+		}
+
+		if err := c.inflight.checkOpen(); err != nil {
+			return results, fmt.Errorf("clip %d: %w", i, err)
+		}
+		result, err := c.generateAndWait(ctx, imageURL, prompt, clipOpts...)
+		if err != nil {
+			if result != nil {
+				results = append(results, result)
+			}
+			return results, fmt.Errorf("clip %d: %w", i, err)
+		}
+		results = append(results, result)
+
+		if i == len(clips)-1 {
+			break
+		}
+		lastFrame, err = c.extractLastFrame(ctx, result, tempDir, i)
+		if err != nil {
+			return results, fmt.Errorf("clip %d: %w", i, err)
+		}
+	}
+
+	return results, nil
+}
+
+// splitDuration breaks total into clip lengths taken from supported, largest
+// first, rounding the last clip up to the shortest supported length when
+// nothing fits. An empty supported list allows any length up to
+// soraMaxDuration.
+func splitDuration(total int, supported []int) []int {
+	if total <= 0 {
+		return nil
+	}
+
+	var clips []int
+	if len(supported) == 0 {
+		for ; total > soraMaxDuration; total -= soraMaxDuration {
+			clips = append(clips, soraMaxDuration)
+		}
+		return append(clips, total)
+	}
+
+	lengths := slices.Clone(supported)
+	slices.Sort(lengths)
+	for total > 0 {
+		next := lengths[0]
+		for _, d := range lengths {
+			if d <= total {
+				next = d
+			}
+		}
+		clips = append(clips, next)
+		total -= next
+	}
+	return clips
+}
+
+// withOnlyInputImageFile replaces every reference image set so far with the
+// file at path.
+func withOnlyInputImageFile(path string) VideoOption {
+	return func(o *VideoOptions) {
+		o.InputImageReader = nil
+		o.InputImageName = ""
+		o.InputImageURL = ""
+		o.InputImageURLs = nil
+		o.InputImagePaths = nil
+		o.InputImagePath = path
+	}
+}
+
+// extractLastFrame downloads result into dir and saves its final frame as a
+// PNG, returning the image path.
+func (c *OpenAISoraClient) extractLastFrame(ctx context.Context, result *VideoResult, dir string, index int) (string, error) {
+	clipPath := filepath.Join(dir, fmt.Sprintf("clip_%03d.mp4", index))
+	if err := c.downloadToFile(ctx, result, clipPath); err != nil {
+		return "", fmt.Errorf("download clip: %w", err)
+	}
+
+	framePath := filepath.Join(dir, fmt.Sprintf("frame_%03d.png", index))
+	cmd := exec.CommandContext(ctx, "ffmpeg",
+		"-sseof", "-0.1",
+		"-i", clipPath,
+		"-frames:v", "1",
+		"-update", "1",
+		"-y",
+		framePath,
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("extract last frame: %w, output: %s", err, string(output))
+	}
+	return framePath, nil
+}
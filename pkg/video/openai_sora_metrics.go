@@ -0,0 +1,26 @@
+package video
+
+import "time"
+
+// MetricsCollector receives request metrics from the Sora client so callers
+// can export them (e.g. to Prometheus) without the package depending on a
+// metrics library. op is one of "generate", "remix", "create", "start",
+// "status", "stream", "cancel", "list", "delete" or "ping"; status is 0
+// when no response was received.
+type MetricsCollector interface {
+	ObserveRequest(op string, status int, dur time.Duration)
+	IncRetry(op string)
+}
+
+type noopMetrics struct{}
+
+func (noopMetrics) ObserveRequest(string, int, time.Duration) {}
+func (noopMetrics) IncRetry(string)                           {}
+
+// metrics returns c.Metrics, or a no-op collector when it is nil.
+func (c *OpenAISoraClient) metrics() MetricsCollector {
+	if c.Metrics == nil {
+		return noopMetrics{}
+	}
+	return c.Metrics
+}
@@ -0,0 +1,146 @@
+package video
+
+import (
+	"net/http"
+	"time"
+)
+
+// SoraClientOption configures an OpenAISoraClient at construction time.
+type SoraClientOption func(*OpenAISoraClient)
+
+// WithTimeout sets the overall timeout of the underlying HTTP client. It has
+// no effect when HTTPClient is not an *http.Client, and doesn't touch
+// StatusHTTPClient.
+func WithTimeout(d time.Duration) SoraClientOption {
+	return func(c *OpenAISoraClient) {
+		if hc, ok := c.HTTPClient.(*http.Client); ok {
+			copied := *hc
+			copied.Timeout = d
+			c.HTTPClient = &copied
+		}
+	}
+}
+
+// WithGenerateTimeout bounds each GenerateVideo call, including retries.
+func WithGenerateTimeout(d time.Duration) SoraClientOption {
+	return func(c *OpenAISoraClient) {
+		c.GenerateTimeout = d
+	}
+}
+
+// WithStatusTimeout bounds each GetTaskStatus call, including retries.
+func WithStatusTimeout(d time.Duration) SoraClientOption {
+	return func(c *OpenAISoraClient) {
+		c.StatusTimeout = d
+	}
+}
+
+// WithHTTPClient replaces the default HTTP client, e.g. to share a transport
+// with connection pooling. Options applied after it act on a copy.
+func WithHTTPClient(hc *http.Client) SoraClientOption {
+	return func(c *OpenAISoraClient) {
+		c.HTTPClient = hc
+	}
+}
+
+// WithDoer sends every request through d instead of an *http.Client, e.g. a
+// stub returning canned responses in tests. Redirects and timeouts are then
+// up to d.
+func WithDoer(d Doer) SoraClientOption {
+	return func(c *OpenAISoraClient) {
+		c.HTTPClient = d
+	}
+}
+
+// WithStatusHTTPClient sends status polls, ListTasks and Ping through d,
+// leaving HTTPClient for generate calls and downloads. A generate POST may
+// upload several megabytes of reference images, while a status poll should
+// answer within seconds, so one total timeout rarely suits both:
+//
+//	sora := video.NewOpenAISoraClient(baseURL, apiKey, "sora-2",
+//		video.WithTimeout(5*time.Minute),
+//		video.WithStatusHTTPClient(&http.Client{Timeout: 15 * time.Second}),
+//	)
+func WithStatusHTTPClient(d Doer) SoraClientOption {
+	return func(c *OpenAISoraClient) {
+		c.StatusHTTPClient = d
+	}
+}
+
+// WithBatchStatusPath enables a gateway's bulk status endpoint for
+// GetTaskStatuses, e.g. "/videos/status".
+func WithBatchStatusPath(path string) SoraClientOption {
+	return func(c *OpenAISoraClient) {
+		c.BatchStatusPath = path
+	}
+}
+
+// WithHeader adds a header sent with every API request. It cannot replace
+// the Authorization header, which always comes from the API key or
+// TokenSource.
+func WithHeader(key, value string) SoraClientOption {
+	return func(c *OpenAISoraClient) {
+		if c.Headers == nil {
+			c.Headers = http.Header{}
+		} else {
+			c.Headers = c.Headers.Clone()
+		}
+		c.Headers.Add(key, value)
+	}
+}
+
+// WithTokenSource makes the client fetch the Authorization token from ts
+// for every request instead of using the static API key.
+func WithTokenSource(ts TokenSource) SoraClientOption {
+	return func(c *OpenAISoraClient) {
+		c.TokenSource = ts
+	}
+}
+
+// WithUserAgent replaces DefaultUserAgent, e.g. so a provider can
+// whitelist traffic from a specific application. A User-Agent given with
+// WithHeader still takes precedence on API requests.
+func WithUserAgent(ua string) SoraClientOption {
+	return func(c *OpenAISoraClient) {
+		c.UserAgent = ua
+	}
+}
+
+// WithTransport sets the transport of the HTTP client while keeping its
+// timeout and other settings; like WithTimeout it needs an *http.Client. For
+// an authenticated forward proxy with a custom CA, pass something like:
+//
+//	&http.Transport{
+//		Proxy:           http.ProxyURL(proxyURL), // user:pass@host in the URL
+//		TLSClientConfig: &tls.Config{RootCAs: pool},
+//	}
+//
+// The client's Timeout covers the whole exchange, body included. To fail
+// fast on an unreachable or hung server without capping slow uploads and
+// downloads, bound the individual phases instead. Generation itself runs
+// asynchronously, so none of these has to cover rendering time:
+//
+//	&http.Transport{
+//		Proxy:                 http.ProxyFromEnvironment,
+//		DialContext:           (&net.Dialer{Timeout: 10 * time.Second, KeepAlive: 30 * time.Second}).DialContext,
+//		TLSHandshakeTimeout:   10 * time.Second,
+//		ResponseHeaderTimeout: 60 * time.Second, // time from sending the body to the first response byte
+//		IdleConnTimeout:       90 * time.Second,
+//	}
+func WithTransport(rt http.RoundTripper) SoraClientOption {
+	return func(c *OpenAISoraClient) {
+		if hc, ok := c.HTTPClient.(*http.Client); ok {
+			copied := *hc
+			copied.Transport = rt
+			c.HTTPClient = &copied
+		}
+	}
+}
+
+// WithRequestEncoding selects multipart/form-data (the default) or JSON
+// bodies for generate requests.
+func WithRequestEncoding(enc Encoding) SoraClientOption {
+	return func(c *OpenAISoraClient) {
+		c.RequestEncoding = enc
+	}
+}
@@ -0,0 +1,251 @@
+package video
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrTaskCancelled is returned by WaitForCompletion when the task was
+// cancelled, e.g. through CancelTask.
+var ErrTaskCancelled = errors.New("video task cancelled")
+
+// TaskFailedError is returned by WaitForCompletion when the provider reports
+// the task as failed.
+type TaskFailedError struct {
+	TaskID  string
+	Message string
+	Result  *VideoResult
+}
+
+func (e *TaskFailedError) Error() string {
+	return fmt.Sprintf("video task %s failed: %s", e.TaskID, e.Message)
+}
+
+// TimeoutError is returned when the polling deadline passes before the task
+// finishes. Result holds the last status seen, if any.
+type TimeoutError struct {
+	TaskID   string
+	Progress int
+	Result   *VideoResult
+	Err      error
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("video task %s still running at %d%% when the deadline passed: %v", e.TaskID, e.Progress, e.Err)
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// StalledError is returned by WaitForCompletion when a task's progress and
+// status have not changed for the client's StallTimeout. The task is left
+// running; callers usually cancel it and resubmit.
+type StalledError struct {
+	TaskID   string
+	Progress int
+	Stalled  time.Duration
+	Result   *VideoResult
+}
+
+func (e *StalledError) Error() string {
+	return fmt.Sprintf("video task %s stalled at %d%% for %s", e.TaskID, e.Progress, e.Stalled.Round(time.Second))
+}
+
+// PollStrategy controls how often WaitForCompletion polls. The interval
+// starts at Initial and is multiplied by Multiplier after every poll up to
+// Max, dropping back to Initial whenever the task's progress changes.
+type PollStrategy struct {
+	Initial    time.Duration
+	Max        time.Duration
+	Multiplier float64
+}
+
+// DefaultPollStrategy polls after 2s, 4s, 8s, ... up to every 30s.
+var DefaultPollStrategy = PollStrategy{
+	Initial:    2 * time.Second,
+	Max:        30 * time.Second,
+	Multiplier: 2,
+}
+
+// next returns the interval that follows current.
+func (s PollStrategy) next(current time.Duration) time.Duration {
+	if s.Multiplier <= 1 {
+		return current
+	}
+	next := time.Duration(float64(current) * s.Multiplier)
+	if s.Max > 0 && next > s.Max {
+		next = s.Max
+	}
+	return next
+}
+
+// ProgressFunc receives the task progress (0-100) and status while polling.
+type ProgressFunc func(progress int, status VideoStatus)
+
+// WaitForCompletion polls GetTaskStatus until the task is completed, failed
+// or cancelled, or ctx is done; see PollWithProgress for how interval is
+// used. A task reported as completed without a video URL is polled again
+// until the URL shows up, subject to the same deadline and StallTimeout. If ctx has a deadline that passes first, a TimeoutError with the last
+// known status is returned.
+func (c *OpenAISoraClient) WaitForCompletion(ctx context.Context, taskID string, interval time.Duration) (*VideoResult, error) {
+	return c.PollWithProgress(ctx, taskID, interval, nil)
+}
+
+// WaitForCompletionDeadline is like WaitForCompletion but gives up at
+// deadline, returning a TimeoutError that carries the last known status.
+func (c *OpenAISoraClient) WaitForCompletionDeadline(ctx context.Context, taskID string, interval time.Duration, deadline time.Time) (*VideoResult, error) {
+	ctx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+	return c.WaitForCompletion(ctx, taskID, interval)
+}
+
+// PollWithProgress behaves like WaitForCompletion and additionally calls
+// onProgress on the first poll and whenever the reported progress changes.
+// Some providers never fill in progress, so it may stay at 0 until the task
+// finishes; callers should treat 0 as "unknown" rather than "not started".
+//
+// A positive interval polls at that fixed rate. Otherwise the client's
+// PollStrategy is used, or DefaultPollStrategy when it is unset.
+func (c *OpenAISoraClient) PollWithProgress(ctx context.Context, taskID string, interval time.Duration, onProgress ProgressFunc) (*VideoResult, error) {
+	if err := c.inflight.start(); err != nil {
+		return nil, err
+	}
+	defer c.inflight.done()
+
+	return c.pollWithProgress(ctx, taskID, interval, onProgress)
+}
+
+// pollWithProgress is PollWithProgress for callers that already registered
+// with inflight, so a task they submitted is still waited for when Close
+// runs in between.
+func (c *OpenAISoraClient) pollWithProgress(ctx context.Context, taskID string, interval time.Duration, onProgress ProgressFunc) (*VideoResult, error) {
+	strategy := c.PollStrategy
+	if interval > 0 {
+		strategy = PollStrategy{Initial: interval, Max: interval, Multiplier: 1}
+	} else if strategy.Initial <= 0 {
+		strategy = DefaultPollStrategy
+	}
+
+	var last *VideoResult
+	lastProgress := -1
+	var lastStatus VideoStatus
+	lastChange := time.Now()
+	delay := strategy.Initial
+	for {
+		result, err := c.GetTaskStatusContext(ctx, taskID)
+		// A task can report completion before its URL is filled in; keep
+		// polling for it instead of failing
+		missingURL := result != nil && errors.Is(err, ErrMissingVideoURL)
+		if err != nil && !missingURL {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return last, newTimeoutError(taskID, last, ctx.Err())
+			}
+			return result, err
+		}
+		last = result
+
+		if result.Progress != lastProgress {
+			if lastProgress >= 0 {
+				// The task is moving, so look again soon
+				delay = strategy.Initial
+			}
+			if onProgress != nil {
+				onProgress(result.Progress, result.Status)
+			}
+		}
+
+		if result.Progress != lastProgress || result.Status != lastStatus {
+			lastChange = time.Now()
+		}
+		lastProgress, lastStatus = result.Progress, result.Status
+
+		switch {
+		case missingURL:
+			// Not done until the URL is there
+		case result.Status == StatusCompleted:
+			return result, nil
+		case result.Status == StatusFailed:
+			return result, &TaskFailedError{TaskID: taskID, Message: result.Error, Result: result}
+		case result.Status == StatusCancelled:
+			return result, ErrTaskCancelled
+		}
+
+		if stalled := time.Since(lastChange); c.StallTimeout > 0 && stalled >= c.StallTimeout {
+			return result, &StalledError{TaskID: taskID, Progress: result.Progress, Stalled: stalled, Result: result}
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return result, newTimeoutError(taskID, result, ctx.Err())
+			}
+			return result, fmt.Errorf("wait for task %s: %w", taskID, ctx.Err())
+		case <-timer.C:
+		}
+		delay = strategy.next(delay)
+	}
+}
+
+// GenerateAndWait submits a generation and blocks until it finishes,
+// returning the completed result with VideoURL set. Without
+// WithIdempotencyKey only connection failures of the submit are retried; with
+// a key, 429 and 5xx responses are retried too, according to RetryPolicy.
+// Errors that retrying can't fix, such as a ModerationError or
+// QuotaExceededError, are returned straight away. Polling follows
+// PollStrategy.
+//
+// Submit and wait count as one call for Close, which waits for the task to
+// finish rather than abandoning it after the submit. When waiting fails, the
+// last known result, or else the submitted one, is returned with the error so
+// the TaskID is never lost.
+func (c *OpenAISoraClient) GenerateAndWait(ctx context.Context, imageURL, prompt string, opts ...VideoOption) (*VideoResult, error) {
+	if err := c.inflight.start(); err != nil {
+		return nil, err
+	}
+	defer c.inflight.done()
+
+	return c.generateAndWait(ctx, imageURL, prompt, opts...)
+}
+
+// generateAndWait is GenerateAndWait for callers that already registered
+// with inflight.
+func (c *OpenAISoraClient) generateAndWait(ctx context.Context, imageURL, prompt string, opts ...VideoOption) (*VideoResult, error) {
+	submitted, err := c.submitOne(ctx, "generate", c.videosEndpoint(), imageURL, prompt, opts)
+	if err != nil {
+		return submitted, err
+	}
+	if submitted.Completed || submitted.TaskID == DryRunTaskID {
+		return submitted, nil
+	}
+
+	result, err := c.pollWithProgress(ctx, submitted.TaskID, 0, nil)
+	if result == nil {
+		result = submitted
+	}
+	return result, err
+}
+
+// PollOnce checks the task's status a single time without waiting. done
+// reports whether the status is terminal (completed, failed or cancelled),
+// letting an external scheduler persist the result between ticks instead of
+// blocking in WaitForCompletion.
+func (c *OpenAISoraClient) PollOnce(ctx context.Context, taskID string) (result *VideoResult, done bool, err error) {
+	result, err = c.GetTaskStatusContext(ctx, taskID)
+	if result != nil {
+		done = result.Status.IsTerminal()
+	}
+	return result, done, err
+}
+
+func newTimeoutError(taskID string, last *VideoResult, err error) *TimeoutError {
+	timeoutErr := &TimeoutError{TaskID: taskID, Result: last, Err: err}
+	if last != nil {
+		timeoutErr.Progress = last.Progress
+	}
+	return timeoutErr
+}
@@ -0,0 +1,32 @@
+package video
+
+// ResolutionPreset is a named output size that the sora-2 models render.
+// Short-form drama is usually vertical (9:16).
+type ResolutionPreset string
+
+const (
+	Resolution720x1280  ResolutionPreset = "720x1280"  // 9:16 vertical
+	Resolution1024x1792 ResolutionPreset = "1024x1792" // 9:16 vertical, sora-2-pro
+	Resolution1280x720  ResolutionPreset = "1280x720"  // 16:9 landscape
+	Resolution1792x1024 ResolutionPreset = "1792x1024" // 16:9 landscape, sora-2-pro
+)
+
+// presetAspectRatios maps each preset to its aspect ratio. The ratio is not
+// sent to the provider; it only feeds the orientation check against the
+// size.
+var presetAspectRatios = map[ResolutionPreset]string{
+	Resolution720x1280:  "9:16",
+	Resolution1024x1792: "9:16",
+	Resolution1280x720:  "16:9",
+	Resolution1792x1024: "16:9",
+}
+
+// WithPreset sets the resolution together with its aspect ratio, which only
+// serves the orientation check. The size is still checked against the
+// model's supported resolutions.
+func WithPreset(preset ResolutionPreset) VideoOption {
+	return func(o *VideoOptions) {
+		o.Resolution = string(preset)
+		o.AspectRatio = presetAspectRatios[preset]
+	}
+}
@@ -0,0 +1,79 @@
+package video
+
+import "fmt"
+
+// PriceKey selects an entry in a PricingTable.
+type PriceKey struct {
+	Model      string
+	Resolution string // "<width>x<height>"
+}
+
+// PricingTable holds the price per second of video, in USD, for each model
+// and resolution.
+type PricingTable map[PriceKey]float64
+
+// DefaultPricing reflects OpenAI's published Sora prices at the time of
+// writing. Prices change; set OpenAISoraClient.Pricing to keep them current.
+var DefaultPricing = PricingTable{
+	{"sora-2", "1280x720"}:      0.10,
+	{"sora-2", "720x1280"}:      0.10,
+	{"sora-2-pro", "1280x720"}:  0.30,
+	{"sora-2-pro", "720x1280"}:  0.30,
+	{"sora-2-pro", "1792x1024"}: 0.50,
+	{"sora-2-pro", "1024x1792"}: 0.50,
+}
+
+// soraDefaultSize is the size the API renders when none is requested.
+const soraDefaultSize = "720x1280"
+
+// EstimateCost returns the expected price, in USD, of a single generation
+// with opts, using the client's Pricing table or DefaultPricing. The
+// options are resolved and validated the same way GenerateVideo does it,
+// and every candidate requested with WithVariations is charged.
+func (c *OpenAISoraClient) EstimateCost(opts ...VideoOption) (float64, error) {
+	options := &VideoOptions{
+		Duration: 4,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	model := c.Model
+	if options.Model != "" {
+		model = options.Model
+	}
+	if err := validateSoraOptions(options, model); err != nil {
+		return 0, err
+	}
+	size, err := soraSize(options, model)
+	if err != nil {
+		return 0, err
+	}
+	if size == "" {
+		size = soraDefaultSize
+	}
+
+	pricing := c.Pricing
+	if pricing == nil {
+		pricing = DefaultPricing
+	}
+	perSecond, ok := pricing[PriceKey{Model: model, Resolution: size}]
+	if !ok {
+		return 0, fmt.Errorf("no price for model %s at %s", model, size)
+	}
+	return perSecond * float64(options.Duration) * float64(max(1, options.Variations)), nil
+}
+
+// EstimateBatchCost sums EstimateCost over reqs, e.g. to enforce a budget
+// before calling BatchGenerate.
+func (c *OpenAISoraClient) EstimateBatchCost(reqs []GenerateRequest) (float64, error) {
+	var total float64
+	for i, req := range reqs {
+		cost, err := c.EstimateCost(req.videoOptions()...)
+		if err != nil {
+			return 0, fmt.Errorf("request %d: %w", i, err)
+		}
+		total += cost
+	}
+	return total, nil
+}
@@ -0,0 +1,63 @@
+package video
+
+import "context"
+
+// GenerateRequest is a struct form of the GenerateVideo arguments, convenient
+// for jobs deserialized from a queue. Zero values leave the corresponding
+// option unset.
+type GenerateRequest struct {
+	Prompt         string `json:"prompt"`
+	InputReference string `json:"input_reference,omitempty"` // image URL or data URI
+	Model          string `json:"model,omitempty"`
+	Duration       int    `json:"duration,omitempty"`
+	Resolution     string `json:"resolution,omitempty"`
+	AspectRatio    string `json:"aspect_ratio,omitempty"`
+	FPS            int    `json:"fps,omitempty"`
+	Quality        string `json:"quality,omitempty"`
+	Seed           int64  `json:"seed,omitempty"`
+	NegativePrompt string `json:"negative_prompt,omitempty"`
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+
+	// Options are applied after the fields above and can set anything the
+	// fields don't cover.
+	Options []VideoOption `json:"-"`
+}
+
+// videoOptions converts the request fields into VideoOptions.
+func (r GenerateRequest) videoOptions() []VideoOption {
+	var opts []VideoOption
+	if r.Model != "" {
+		opts = append(opts, WithModel(r.Model))
+	}
+	if r.Duration != 0 {
+		opts = append(opts, WithDuration(r.Duration))
+	}
+	if r.Resolution != "" {
+		opts = append(opts, WithResolution(r.Resolution))
+	}
+	if r.AspectRatio != "" {
+		opts = append(opts, WithAspectRatio(r.AspectRatio))
+	}
+	if r.FPS != 0 {
+		opts = append(opts, WithFPS(r.FPS))
+	}
+	if r.Quality != "" {
+		opts = append(opts, WithQuality(r.Quality))
+	}
+	if r.Seed != 0 {
+		opts = append(opts, WithSeed(r.Seed))
+	}
+	if r.NegativePrompt != "" {
+		opts = append(opts, WithNegativePrompt(r.NegativePrompt))
+	}
+	if r.IdempotencyKey != "" {
+		opts = append(opts, WithIdempotencyKey(r.IdempotencyKey))
+	}
+	return append(opts, r.Options...)
+}
+
+// GenerateFromRequest submits req; it is equivalent to GenerateVideoContext
+// with the matching options.
+func (c *OpenAISoraClient) GenerateFromRequest(ctx context.Context, req GenerateRequest) (*VideoResult, error) {
+	return c.submit(ctx, "generate", c.videosEndpoint(), req.InputReference, req.Prompt, req.videoOptions())
+}
@@ -0,0 +1,295 @@
+package video
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy controls how the Sora client retries requests that fail with
+// 429 or a transient 5xx status. A zero MaxRetries disables retrying. A
+// Retry-After header from the server is honoured up to MaxDelay.
+type RetryPolicy struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+}
+
+// DefaultRetryPolicy is used by NewOpenAISoraClient.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	BaseDelay:  1 * time.Second,
+	MaxDelay:   30 * time.Second,
+}
+
+// RateLimiter throttles outgoing requests. *rate.Limiter from
+// golang.org/x/time/rate satisfies it.
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+}
+
+func isRetryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// backoff returns the delay before retry number attempt (starting at 0),
+// using full jitter: a random duration in [0, min(MaxDelay, BaseDelay*2^attempt)).
+// Spreading retries over the whole window keeps workers that hit a 429 at
+// the same moment from retrying in lockstep. A zero BaseDelay or MaxDelay
+// falls back to DefaultRetryPolicy's.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	base := p.BaseDelay
+	if base <= 0 {
+		base = DefaultRetryPolicy.BaseDelay
+	}
+	maxDelay := p.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = DefaultRetryPolicy.MaxDelay
+	}
+
+	ceiling := maxDelay
+	if attempt < 32 {
+		if delay := base << uint(attempt); delay > 0 && delay < maxDelay {
+			ceiling = delay
+		}
+	}
+
+	if ceiling <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(ceiling)))
+}
+
+// retryAfterDelay turns a server's Retry-After into the delay before the
+// next attempt. It is capped at MaxDelay, so a server asking for an hour
+// doesn't stall the call, and gets up to a quarter of itself (at least
+// BaseDelay) of random jitter on top, so workers that got the same header
+// don't all retry at the same instant.
+func (p RetryPolicy) retryAfterDelay(retryAfter time.Duration) time.Duration {
+	base := p.BaseDelay
+	if base <= 0 {
+		base = DefaultRetryPolicy.BaseDelay
+	}
+	maxDelay := p.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = DefaultRetryPolicy.MaxDelay
+	}
+
+	delay := min(retryAfter, maxDelay)
+	spread := max(delay/4, base)
+	return delay + time.Duration(rand.Int63n(int64(spread)))
+}
+
+// parseRetryAfter reads a Retry-After header given either in seconds or as an
+// HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+// newResponseMeta collects the rate-limit headers of resp.
+func newResponseMeta(resp *http.Response) *ResponseMeta {
+	meta := &ResponseMeta{StatusCode: resp.StatusCode, RateLimit: http.Header{}}
+	if d, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+		meta.RetryAfter = d
+	}
+	for key, values := range resp.Header {
+		if strings.HasPrefix(key, "X-Ratelimit-") {
+			meta.RateLimit[key] = values
+		}
+	}
+	return meta
+}
+
+// doRequest sends the request produced by newReq and reads the whole response
+// body. Retryable statuses and temporary transport errors (see
+// TransportError) are retried according to c.RetryPolicy; newReq is
+// called again for every attempt so the body can be replayed. op names the
+// operation for metrics.
+func (c *OpenAISoraClient) doRequest(ctx context.Context, op string, newReq func() (*http.Request, error)) (*http.Response, []byte, error) {
+	return c.doRequestRetry(ctx, op, true, newReq)
+}
+
+// doRequestRetry is doRequest for requests that may not be safe to repeat.
+// When replayable is false, such as for a generate POST without a caller
+// supplied idempotency key, only failures that happened before the request
+// could reach the server (refused connections, DNS) are retried; once any
+// HTTP status has been received, or the connection broke mid-request, the
+// result is returned as is so a task is never created twice.
+func (c *OpenAISoraClient) doRequestRetry(ctx context.Context, op string, replayable bool, newReq func() (*http.Request, error)) (*http.Response, []byte, error) {
+	for attempt := 0; ; attempt++ {
+		// Waiting on every attempt keeps retries within the limit too; a 429
+		// backoff is added on top of it.
+		if c.RateLimiter != nil {
+			if err := c.RateLimiter.Wait(ctx); err != nil {
+				return nil, nil, fmt.Errorf("rate limiter: %w", err)
+			}
+		}
+
+		req, err := newReq()
+		if err != nil {
+			return nil, nil, fmt.Errorf("create request: %w", err)
+		}
+
+		start := time.Now()
+		resp, err := c.doerFor(op).Do(req)
+		if err != nil {
+			c.metrics().ObserveRequest(op, 0, time.Since(start))
+			c.logContext(ctx, LogLevelWarn, "sora request failed", "method", req.Method, "endpoint", req.URL.Path, "attempt", attempt, "duration", time.Since(start), "error", err)
+			if ctx.Err() != nil {
+				return nil, nil, fmt.Errorf("request cancelled: %w", ctx.Err())
+			}
+			transportErr := &TransportError{Op: op, Err: err}
+			if !transportErr.Temporary() || (!replayable && !isDialError(err)) || attempt >= c.RetryPolicy.MaxRetries {
+				return nil, nil, transportErr
+			}
+			if err := c.retryWait(ctx, op, attempt+1, transportErr, c.RetryPolicy.backoff(attempt)); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			c.metrics().ObserveRequest(op, resp.StatusCode, time.Since(start))
+			c.logContext(ctx, LogLevelWarn, "sora response read failed", "method", req.Method, "endpoint", req.URL.Path, "status", resp.StatusCode, "attempt", attempt, "duration", time.Since(start), "error", err)
+			if ctx.Err() != nil {
+				return nil, nil, fmt.Errorf("request cancelled: %w", ctx.Err())
+			}
+			// The request reached the server, so this is never a dial error
+			// and is only retried when the request is replayable.
+			transportErr := &TransportError{Op: op, Err: fmt.Errorf("read response: %w", err)}
+			if !transportErr.Temporary() || !replayable || attempt >= c.RetryPolicy.MaxRetries {
+				return nil, nil, transportErr
+			}
+			if err := c.retryWait(ctx, op, attempt+1, transportErr, c.RetryPolicy.backoff(attempt)); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+
+		c.metrics().ObserveRequest(op, resp.StatusCode, time.Since(start))
+		c.logContext(ctx, LogLevelInfo, "sora request", "method", req.Method, "endpoint", req.URL.Path, "status", resp.StatusCode, "attempt", attempt, "duration", time.Since(start))
+
+		if !replayable || !isRetryableStatus(resp.StatusCode) || attempt >= c.RetryPolicy.MaxRetries {
+			return resp, body, nil
+		}
+		apiErr := newAPIError(resp, body)
+		if resp.StatusCode == http.StatusTooManyRequests && apiErr.Type == quotaErrorType {
+			// Out of credits rather than rate limited; waiting won't help
+			return resp, body, nil
+		}
+
+		delay := c.RetryPolicy.backoff(attempt)
+		if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			delay = c.RetryPolicy.retryAfterDelay(retryAfter)
+		}
+
+		if err := c.retryWait(ctx, op, attempt+1, apiErr, delay); err != nil {
+			return nil, nil, err
+		}
+	}
+}
+
+// openStream is doRequest for streaming responses: it sends the request
+// produced by newReq and returns the response with its body unread, for the
+// caller to consume and close. Only temporary transport errors are retried;
+// any HTTP status is returned as is.
+func (c *OpenAISoraClient) openStream(ctx context.Context, op string, newReq func() (*http.Request, error)) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if c.RateLimiter != nil {
+			if err := c.RateLimiter.Wait(ctx); err != nil {
+				return nil, fmt.Errorf("rate limiter: %w", err)
+			}
+		}
+
+		req, err := newReq()
+		if err != nil {
+			return nil, fmt.Errorf("create request: %w", err)
+		}
+
+		start := time.Now()
+		resp, err := c.doerFor(op).Do(req)
+		if err == nil {
+			c.metrics().ObserveRequest(op, resp.StatusCode, time.Since(start))
+			c.logContext(ctx, LogLevelInfo, "sora request", "method", req.Method, "endpoint", req.URL.Path, "status", resp.StatusCode, "attempt", attempt, "duration", time.Since(start))
+			return resp, nil
+		}
+
+		c.metrics().ObserveRequest(op, 0, time.Since(start))
+		c.logContext(ctx, LogLevelWarn, "sora request failed", "method", req.Method, "endpoint", req.URL.Path, "attempt", attempt, "duration", time.Since(start), "error", err)
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("request cancelled: %w", ctx.Err())
+		}
+		transportErr := &TransportError{Op: op, Err: err}
+		if !transportErr.Temporary() || attempt >= c.RetryPolicy.MaxRetries {
+			return nil, transportErr
+		}
+		if err := c.retryWait(ctx, op, attempt+1, transportErr, c.RetryPolicy.backoff(attempt)); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// doerFor returns the client that sends requests for op.
+func (c *OpenAISoraClient) doerFor(op string) Doer {
+	switch op {
+	case "status", "list", "ping":
+		if c.StatusHTTPClient != nil {
+			return c.StatusHTTPClient
+		}
+	}
+	return c.HTTPClient
+}
+
+// isDialError reports whether err happened while connecting, before any
+// part of the request was sent.
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// retryWait reports retry number attempt of op, caused by cause, and sleeps
+// for delay or until ctx is done.
+func (c *OpenAISoraClient) retryWait(ctx context.Context, op string, attempt int, cause error, delay time.Duration) error {
+	c.metrics().IncRetry(op)
+	if c.OnRetry != nil {
+		c.OnRetry(attempt, cause, delay)
+	}
+	timer := time.NewTimer(delay)
+	select {
+	case <-ctx.Done():
+		timer.Stop()
+		return fmt.Errorf("request cancelled: %w", ctx.Err())
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,269 @@
+package video
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var fastRetryPolicy = RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
+// countingTransport counts round trips, including those that fail to connect.
+type countingTransport struct {
+	count atomic.Int32
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.count.Add(1)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// newFlakyServer answers the first failures requests to each method with
+// 503 and the rest normally, counting requests by method.
+func newFlakyServer(t *testing.T, failures int32, posts, gets *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		counter := gets
+		if r.Method == "POST" {
+			counter = posts
+		}
+		if counter.Add(1) <= failures {
+			http.Error(w, `{"error":{"message":"overloaded"}}`, http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"video_123","status":"queued"}`)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGenerateWithoutIdempotencyKeyIsNotRetriedAfterStatus(t *testing.T) {
+	var posts, gets atomic.Int32
+	server := newFlakyServer(t, 1, &posts, &gets)
+	client := NewOpenAISoraClient(server.URL, "test-key", "sora-2")
+	client.RetryPolicy = fastRetryPolicy
+
+	_, err := client.GenerateVideoContext(context.Background(), "", "a cat")
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("GenerateVideoContext() error = %v, want 503 APIError", err)
+	}
+	if n := posts.Load(); n != 1 {
+		t.Errorf("server received %d POSTs, want 1", n)
+	}
+}
+
+func TestGenerateWithoutIdempotencyKeyIsNotRetriedAfterBrokenConnection(t *testing.T) {
+	var posts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posts.Add(1)
+		// Drop the connection after the request arrived, like a timeout
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			conn.Close()
+		}
+	}))
+	defer server.Close()
+
+	client := NewOpenAISoraClient(server.URL, "test-key", "sora-2")
+	client.RetryPolicy = fastRetryPolicy
+
+	_, err := client.GenerateVideoContext(context.Background(), "", "a cat")
+
+	var transportErr *TransportError
+	if !errors.As(err, &transportErr) {
+		t.Fatalf("GenerateVideoContext() error = %v, want TransportError", err)
+	}
+	if n := posts.Load(); n != 1 {
+		t.Errorf("server received %d POSTs, want 1", n)
+	}
+}
+
+func TestGenerateWithIdempotencyKeyIsRetried(t *testing.T) {
+	var posts, gets atomic.Int32
+	server := newFlakyServer(t, 1, &posts, &gets)
+	client := NewOpenAISoraClient(server.URL, "test-key", "sora-2")
+	client.RetryPolicy = fastRetryPolicy
+
+	result, err := client.GenerateVideoContext(context.Background(), "", "a cat", WithIdempotencyKey("scene-1"))
+	if err != nil {
+		t.Fatalf("GenerateVideoContext() error = %v", err)
+	}
+	if result.TaskID != "video_123" {
+		t.Errorf("TaskID = %q, want video_123", result.TaskID)
+	}
+	if n := posts.Load(); n != 2 {
+		t.Errorf("server received %d POSTs, want 2", n)
+	}
+}
+
+func TestGenerateWithoutIdempotencyKeyRetriesRefusedConnection(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	transport := &countingTransport{}
+	client := NewOpenAISoraClient(url, "test-key", "sora-2", WithTransport(transport))
+	client.RetryPolicy = fastRetryPolicy
+
+	_, err := client.GenerateVideoContext(context.Background(), "", "a cat")
+
+	var transportErr *TransportError
+	if !errors.As(err, &transportErr) {
+		t.Fatalf("GenerateVideoContext() error = %v, want TransportError", err)
+	}
+	if n, want := transport.count.Load(), int32(fastRetryPolicy.MaxRetries+1); n != want {
+		t.Errorf("made %d attempts, want %d", n, want)
+	}
+}
+
+func TestGetTaskStatusRetriesTruncatedBody(t *testing.T) {
+	var gets atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := `{"id":"video_123","status":"queued"}`
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Length", fmt.Sprint(len(body)))
+		if gets.Add(1) == 1 {
+			// Promise the whole body but close the connection halfway
+			fmt.Fprint(w, body[:10])
+			w.(http.Flusher).Flush()
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+			return
+		}
+		fmt.Fprint(w, body)
+	}))
+	defer server.Close()
+
+	client := NewOpenAISoraClient(server.URL, "test-key", "sora-2")
+	client.RetryPolicy = fastRetryPolicy
+
+	result, err := client.GetTaskStatusContext(context.Background(), "video_123")
+	if err != nil {
+		t.Fatalf("GetTaskStatusContext() error = %v", err)
+	}
+	if result.TaskID != "video_123" {
+		t.Errorf("TaskID = %q, want video_123", result.TaskID)
+	}
+	if n := gets.Load(); n != 2 {
+		t.Errorf("server received %d GETs, want 2", n)
+	}
+}
+
+func TestGetTaskStatusIsRetried(t *testing.T) {
+	var posts, gets atomic.Int32
+	server := newFlakyServer(t, 2, &posts, &gets)
+	client := NewOpenAISoraClient(server.URL, "test-key", "sora-2")
+	client.RetryPolicy = fastRetryPolicy
+
+	if _, err := client.GetTaskStatus("video_123"); err != nil {
+		t.Fatalf("GetTaskStatus() error = %v", err)
+	}
+	if n := gets.Load(); n != 3 {
+		t.Errorf("server received %d GETs, want 3", n)
+	}
+}
+
+func TestBackoffFullJitterWithinCap(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 10, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	tests := []struct {
+		attempt int
+		ceiling time.Duration
+	}{
+		{0, 100 * time.Millisecond},
+		{1, 200 * time.Millisecond},
+		{2, 400 * time.Millisecond},
+		{3, 800 * time.Millisecond},
+		{4, time.Second},
+		{10, time.Second},
+		{63, time.Second},
+		{100, time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("attempt %d", tt.attempt), func(t *testing.T) {
+			var lower, upper bool
+			for i := 0; i < 1000; i++ {
+				delay := policy.backoff(tt.attempt)
+				if delay < 0 || delay >= tt.ceiling {
+					t.Fatalf("backoff(%d) = %v, want [0, %v)", tt.attempt, delay, tt.ceiling)
+				}
+				lower = lower || delay < tt.ceiling/4
+				upper = upper || delay >= tt.ceiling*3/4
+			}
+			// Full jitter spreads delays over the whole window
+			if !lower || !upper {
+				t.Errorf("backoff(%d) not spread over [0, %v): low quarter hit %v, high quarter hit %v", tt.attempt, tt.ceiling, lower, upper)
+			}
+		})
+	}
+}
+
+func TestBackoffZeroMaxDelayUsesDefaultCap(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: time.Second}
+	for i := 0; i < 100; i++ {
+		if delay := policy.backoff(20); delay >= DefaultRetryPolicy.MaxDelay {
+			t.Fatalf("backoff(20) = %v, want below %v", delay, DefaultRetryPolicy.MaxDelay)
+		}
+	}
+}
+
+func TestRetryAfterIsCappedAtMaxDelay(t *testing.T) {
+	var gets atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if gets.Add(1) == 1 {
+			w.Header().Set("Retry-After", "3600")
+			http.Error(w, `{"error":{"message":"slow down"}}`, http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"video_123","status":"queued"}`)
+	}))
+	defer server.Close()
+
+	client := NewOpenAISoraClient(server.URL, "test-key", "sora-2")
+	client.RetryPolicy = RetryPolicy{MaxRetries: 1, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+	var delay time.Duration
+	client.OnRetry = func(attempt int, cause error, d time.Duration) {
+		delay = d
+	}
+
+	if _, err := client.GetTaskStatusContext(context.Background(), "video_123"); err != nil {
+		t.Fatalf("GetTaskStatusContext() error = %v", err)
+	}
+	// MaxDelay plus at most a quarter of it as jitter
+	if limit := 10*time.Millisecond + 10*time.Millisecond/4; delay < 10*time.Millisecond || delay >= limit {
+		t.Errorf("retry delay = %v, want in [10ms, %v)", delay, limit)
+	}
+}
+
+func TestStreamStatusReturnsTransportError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	transport := &countingTransport{}
+	client := NewOpenAISoraClient(url, "test-key", "sora-2", WithTransport(transport))
+	client.RetryPolicy = fastRetryPolicy
+
+	err := client.StreamStatus(context.Background(), "video_123", func(*VideoResult) {})
+
+	var transportErr *TransportError
+	if !errors.As(err, &transportErr) || !transportErr.Temporary() {
+		t.Fatalf("StreamStatus() error = %v, want temporary TransportError", err)
+	}
+	if n, want := transport.count.Load(), int32(fastRetryPolicy.MaxRetries+1); n != want {
+		t.Errorf("made %d attempts, want %d", n, want)
+	}
+}
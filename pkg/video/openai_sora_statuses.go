@@ -0,0 +1,153 @@
+package video
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// statusConcurrency caps the individual status calls GetTaskStatuses makes
+// at once when there is no batch endpoint.
+const statusConcurrency = 8
+
+// GetTaskStatuses fetches the status of every task in taskIDs and returns the
+// results in the same order. When BatchStatusPath is set, the tasks are
+// fetched in a single POST to that endpoint; otherwise, or when the
+// provider answers it with 404, 405 or 501, they are fetched through
+// concurrent GetTaskStatus calls.
+//
+// A task that can't be fetched leaves a nil slot (or, for
+// ErrMissingVideoURL, its result) and adds an error naming its ID to the
+// returned error, which joins all of them; errors.Is(err, ErrTaskNotFound)
+// therefore reports whether any task was unknown.
+func (c *OpenAISoraClient) GetTaskStatuses(ctx context.Context, taskIDs []string) ([]*VideoResult, error) {
+	if len(taskIDs) == 0 {
+		return nil, nil
+	}
+
+	if c.BatchStatusPath != "" {
+		results, err := c.batchTaskStatuses(ctx, taskIDs)
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || !batchUnsupported(apiErr.StatusCode) {
+			return results, err
+		}
+		c.logContext(ctx, LogLevelWarn, "sora batch status endpoint unavailable, polling tasks one by one", "status", apiErr.StatusCode)
+	}
+
+	return c.concurrentTaskStatuses(ctx, taskIDs)
+}
+
+// batchUnsupported reports whether a batch status response means the
+// endpoint doesn't exist rather than that the request failed.
+func batchUnsupported(code int) bool {
+	return code == http.StatusNotFound || code == http.StatusMethodNotAllowed || code == http.StatusNotImplemented
+}
+
+// soraStatusList is the response of the batch status endpoint.
+type soraStatusList struct {
+	Data []json.RawMessage `json:"data"`
+}
+
+// batchTaskStatuses sends {"ids": [...]} to BatchStatusPath and matches the
+// returned task objects to taskIDs by ID.
+func (c *OpenAISoraClient) batchTaskStatuses(ctx context.Context, taskIDs []string) ([]*VideoResult, error) {
+	if c.StatusTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.StatusTimeout)
+		defer cancel()
+	}
+
+	payload, err := json.Marshal(map[string][]string{"ids": taskIDs})
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	endpoint := c.BaseURL + "/" + strings.Trim(c.BatchStatusPath, "/")
+	resp, body, err := c.doRequest(ctx, "status", func() (*http.Request, error) {
+		req, err := c.newRequest(ctx, "POST", endpoint, bytes.NewReader(payload))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, newAPIError(resp, body)
+	}
+
+	var list soraStatusList
+	if err := decodeResponse(resp.StatusCode, body, &list); err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*VideoResult, len(list.Data))
+	meta := newResponseMeta(resp)
+	for _, raw := range list.Data {
+		videoResult, err := c.parseTaskObject(raw)
+		if err != nil {
+			return nil, err
+		}
+		videoResult.RawResponse = raw
+		videoResult.ResponseMeta = meta
+		byID[videoResult.TaskID] = videoResult
+	}
+
+	results := make([]*VideoResult, len(taskIDs))
+	var errs []error
+	for i, taskID := range taskIDs {
+		videoResult, ok := byID[taskID]
+		if !ok {
+			errs = append(errs, fmt.Errorf("task %s: %w", taskID, ErrTaskNotFound))
+			continue
+		}
+		results[i] = videoResult
+		if videoResult.Completed && videoResult.VideoURL == "" {
+			errs = append(errs, fmt.Errorf("task %s: %w", taskID, ErrMissingVideoURL))
+		}
+	}
+
+	return results, errors.Join(errs...)
+}
+
+// parseTaskObject parses a single task object, through ResponseMapper when
+// one is set.
+func (c *OpenAISoraClient) parseTaskObject(raw []byte) (*VideoResult, error) {
+	if c.ResponseMapper != nil {
+		return c.mapResponse(raw)
+	}
+
+	var result OpenAISoraResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("parse task: %w", err)
+	}
+	return parseVideoResult(&result), nil
+}
+
+// concurrentTaskStatuses calls GetTaskStatusContext for every task, at most
+// statusConcurrency at a time. Once ctx is done the remaining tasks are not
+// fetched and get ctx.Err().
+func (c *OpenAISoraClient) concurrentTaskStatuses(ctx context.Context, taskIDs []string) ([]*VideoResult, error) {
+	results := make([]*VideoResult, len(taskIDs))
+	errs := make([]error, len(taskIDs))
+
+	started := forEachBounded(ctx, len(taskIDs), statusConcurrency, func(i int) {
+		result, err := c.GetTaskStatusContext(ctx, taskIDs[i])
+		results[i] = result
+		if err != nil {
+			errs[i] = fmt.Errorf("task %s: %w", taskIDs[i], err)
+		}
+	})
+	for i := started; i < len(taskIDs); i++ {
+		errs[i] = fmt.Errorf("task %s: %w", taskIDs[i], ctx.Err())
+	}
+
+	return results, errors.Join(errs...)
+}
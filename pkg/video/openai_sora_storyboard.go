@@ -0,0 +1,153 @@
+package video
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// sceneCancelTimeout bounds the CancelTask call for a scene abandoned
+// because another scene failed permanently.
+const sceneCancelTimeout = 30 * time.Second
+
+// errStoryboardAborted is the cancel cause of RenderStoryboard's context
+// when a scene fails permanently.
+var errStoryboardAborted = errors.New("storyboard aborted by a failed scene")
+
+// Scene is one shot of a storyboard.
+type Scene struct {
+	Prompt         string
+	ReferenceImage string // image URL or data URI, optional
+	Duration       int    // seconds, 0 for the provider default
+}
+
+// Storyboard is an ordered list of scenes rendered into one episode.
+type Storyboard []Scene
+
+// SceneError reports which scene of a storyboard failed.
+type SceneError struct {
+	Index int
+	Err   error
+}
+
+func (e *SceneError) Error() string {
+	return fmt.Sprintf("scene %d: %v", e.Index, e.Err)
+}
+
+func (e *SceneError) Unwrap() error {
+	return e.Err
+}
+
+// RenderStoryboard submits every scene with at most concurrency scenes in
+// flight, waits for them to finish and returns the results in scene order,
+// ready for Concatenate.
+//
+// When a scene fails permanently (rejected, invalid or failed on the
+// provider) the remaining scenes are cancelled, along with their tasks on
+// the provider, and the SceneError is returned. Other failures don't stop the rest of the board; the first of
+// them is returned once every scene has finished. A scene that was submitted
+// but didn't complete keeps its last known result in its slot, so its task
+// can still be found. Close waits for the scenes already submitted; scenes
+// not yet submitted then fail with ErrClientClosed.
+func (c *OpenAISoraClient) RenderStoryboard(ctx context.Context, board Storyboard, concurrency int) ([]*VideoResult, error) {
+	if err := c.inflight.start(); err != nil {
+		return nil, err
+	}
+	defer c.inflight.done()
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	results := make([]*VideoResult, len(board))
+	var (
+		mu       sync.Mutex
+		firstErr error
+		fatal    bool
+	)
+	fail := func(index int, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if fatal {
+			return
+		}
+		if isPermanentError(err) {
+			fatal = true
+			firstErr = &SceneError{Index: index, Err: err}
+			cancel(errStoryboardAborted)
+		} else if firstErr == nil {
+			firstErr = &SceneError{Index: index, Err: err}
+		}
+	}
+
+	forEachBounded(ctx, len(board), concurrency, func(i int) {
+		result, err := c.renderScene(ctx, board[i])
+		results[i] = result
+		if err != nil {
+			fail(i, err)
+		}
+	})
+
+	if firstErr != nil {
+		return results, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return results, err
+	}
+	return results, nil
+}
+
+// renderScene submits one scene and waits for it, under RenderStoryboard's
+// inflight registration. If another scene aborts the storyboard while the
+// task is running, the task is cancelled on the provider as well.
+func (c *OpenAISoraClient) renderScene(ctx context.Context, scene Scene) (*VideoResult, error) {
+	var opts []VideoOption
+	if scene.Duration > 0 {
+		opts = append(opts, WithDuration(scene.Duration))
+	}
+
+	if err := c.inflight.checkOpen(); err != nil {
+		return nil, err
+	}
+	submitted, err := c.submitOne(ctx, "generate", c.videosEndpoint(), scene.ReferenceImage, scene.Prompt, opts)
+	if err != nil {
+		return submitted, err
+	}
+
+	result, err := c.pollWithProgress(ctx, submitted.TaskID, 0, nil)
+	if err != nil && errors.Is(context.Cause(ctx), errStoryboardAborted) {
+		// Stop paying for a render nobody will use. When the caller's ctx
+		// ends instead, the task is left running so it can still be found.
+		cancelCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sceneCancelTimeout)
+		defer cancel()
+		if err := c.CancelTask(cancelCtx, submitted.TaskID); err != nil {
+			c.logContext(ctx, LogLevelWarn, "sora cancel abandoned scene failed", "task_id", submitted.TaskID, "error", err)
+		}
+	}
+	if result == nil {
+		result = submitted
+	}
+	return result, err
+}
+
+// isPermanentError reports whether retrying the same request cannot succeed.
+func isPermanentError(err error) bool {
+	var (
+		validationErr *ValidationError
+		moderationErr *ModerationError
+		quotaErr      *QuotaExceededError
+		failedErr     *TaskFailedError
+		apiErr        *APIError
+	)
+	switch {
+	case errors.As(err, &validationErr), errors.As(err, &moderationErr), errors.As(err, &quotaErr),
+		errors.As(err, &failedErr):
+		return true
+	case errors.As(err, &apiErr):
+		return apiErr.StatusCode >= 400 && apiErr.StatusCode < 500 &&
+			apiErr.StatusCode != http.StatusTooManyRequests
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+package video
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ErrStreamingUnsupported is returned by StreamStatus when the provider has
+// no event stream for tasks; callers should fall back to WaitForCompletion.
+var ErrStreamingUnsupported = errors.New("status streaming not supported by provider")
+
+// StreamStatus subscribes to the Server-Sent Events stream of a task and
+// calls onEvent for every status update. It returns nil once the task
+// reaches a terminal status or the server closes the stream. Opening the
+// stream goes through RateLimiter and RetryPolicy like any other request,
+// and connection failures are returned as a *TransportError. Note that the
+// HTTP client's Timeout also bounds the stream's lifetime.
+func (c *OpenAISoraClient) StreamStatus(ctx context.Context, taskID string, onEvent func(*VideoResult)) error {
+	endpoint := c.videosEndpoint(taskID, "events")
+	resp, err := c.openStream(ctx, "stream", func() (*http.Request, error) {
+		req, err := c.newRequest(ctx, "GET", endpoint, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", "text/event-stream")
+		req.Header.Set("Cache-Control", "no-cache")
+		return req, nil
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return ErrStreamingUnsupported
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		return newAPIError(resp, body)
+	}
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		return ErrStreamingUnsupported
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var data strings.Builder
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if line != "" {
+			// Only data lines matter; event names, ids and comments are ignored
+			if value, ok := strings.CutPrefix(line, "data:"); ok {
+				if data.Len() > 0 {
+					data.WriteByte('\n')
+				}
+				data.WriteString(strings.TrimPrefix(value, " "))
+			}
+			continue
+		}
+
+		// A blank line terminates the event
+		if data.Len() == 0 {
+			continue
+		}
+		payload := data.String()
+		data.Reset()
+
+		if payload == "[DONE]" {
+			return nil
+		}
+
+		var event OpenAISoraResponse
+		if err := json.Unmarshal([]byte(payload), &event); err != nil {
+			return fmt.Errorf("parse event: %w", err)
+		}
+		result := parseVideoResult(&event)
+		if result.TaskID == "" {
+			result.TaskID = taskID
+		}
+		onEvent(result)
+
+		if result.Status.IsTerminal() {
+			return nil
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("stream cancelled: %w", ctx.Err())
+		}
+		return fmt.Errorf("read event stream: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+package video
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	soraMinDuration = 1
+	soraMaxDuration = 20
+)
+
+// DefaultMaxPromptLength is the prompt limit used by NewOpenAISoraClient.
+const DefaultMaxPromptLength = 4000
+
+// soraFPS lists the frame rates accepted by WithFPS.
+var soraFPS = []int{24, 30, 60}
+
+// soraQualities lists the values accepted by WithQuality.
+var soraQualities = []string{"standard", "hd"}
+
+var sizePattern = regexp.MustCompile(`^[1-9][0-9]*x[1-9][0-9]*$`)
+
+// normalizeSize turns variants such as " 1280X720", "1280 x 720" or
+// "1280×720" into the "1280x720" form providers expect. The result is not
+// guaranteed to be valid; sizePattern checks that.
+func normalizeSize(size string) string {
+	size = strings.ToLower(strings.Join(strings.Fields(size), ""))
+	return strings.NewReplacer("×", "x", "*", "x").Replace(size)
+}
+
+// validateSoraOptions checks options locally so obviously bad values fail
+// before a round trip to the API. Models listed in ModelCapabilities are
+// checked against their table entry; others only get generic sanity checks.
+func validateSoraOptions(options *VideoOptions, model string) error {
+	size, err := soraSize(options, model)
+	if err != nil {
+		return err
+	}
+
+	if caps, ok := ModelCapabilities(model); ok {
+		if err := validateCapabilities(options, size, model, caps); err != nil {
+			return err
+		}
+	}
+
+	if options.Duration != 0 && (options.Duration < soraMinDuration || options.Duration > soraMaxDuration) {
+		return &ValidationError{
+			Field:   "duration",
+			Value:   fmt.Sprintf("%d", options.Duration),
+			Allowed: fmt.Sprintf("%d-%d seconds", soraMinDuration, soraMaxDuration),
+		}
+	}
+
+	if options.Resolution != "" && !sizePattern.MatchString(size) {
+		return &ValidationError{
+			Field:   "resolution",
+			Value:   options.Resolution,
+			Allowed: "<width>x<height>, e.g. 1280x720",
+		}
+	}
+
+	if options.Resolution != "" && options.AspectRatio != "" && !sameOrientation(size, options.AspectRatio) {
+		return &ValidationError{
+			Field:   "resolution",
+			Value:   options.Resolution,
+			Allowed: "a size matching aspect ratio " + options.AspectRatio,
+		}
+	}
+
+	if options.MotionStrength != nil && (*options.MotionStrength < 0 || *options.MotionStrength > 100) {
+		return &ValidationError{
+			Field:   "motion strength",
+			Value:   fmt.Sprintf("%d", *options.MotionStrength),
+			Allowed: "0-100",
+		}
+	}
+
+	if options.Variations != 0 && (options.Variations < 1 || options.Variations > MaxVariations) {
+		return &ValidationError{
+			Field:   "variations",
+			Value:   fmt.Sprintf("%d", options.Variations),
+			Allowed: fmt.Sprintf("1-%d", MaxVariations),
+		}
+	}
+
+	if options.FPS != 0 && !slices.Contains(soraFPS, options.FPS) {
+		return &ValidationError{
+			Field:   "fps",
+			Value:   fmt.Sprintf("%d", options.FPS),
+			Allowed: "24, 30, 60",
+		}
+	}
+
+	if options.Quality != "" && !slices.Contains(soraQualities, options.Quality) {
+		return &ValidationError{
+			Field:   "quality",
+			Value:   options.Quality,
+			Allowed: "standard, hd",
+		}
+	}
+
+	return nil
+}
+
+// validateTaskID rejects an empty task ID, which would otherwise address
+// the task collection instead of a task.
+func validateTaskID(taskID string) error {
+	if taskID == "" {
+		return &ValidationError{Field: "task ID", Value: taskID, Allowed: "a non-empty task ID"}
+	}
+	return nil
+}
+
+// validatePromptLength checks the prompts against max characters; max <= 0
+// disables the check.
+func validatePromptLength(prompt, negativePrompt string, max int) error {
+	if max <= 0 {
+		return nil
+	}
+	for _, p := range []struct{ field, value string }{
+		{"prompt", prompt},
+		{"negative prompt", negativePrompt},
+	} {
+		if n := utf8.RuneCountInString(p.value); n > max {
+			return &ValidationError{
+				Field:   p.field,
+				Value:   fmt.Sprintf("%d characters", n),
+				Allowed: fmt.Sprintf("at most %d characters", max),
+			}
+		}
+	}
+	return nil
+}
+
+// validateCapabilities checks options against a model's capability entry.
+func validateCapabilities(options *VideoOptions, size, model string, caps Capabilities) error {
+	if options.Duration != 0 && !slices.Contains(caps.Durations, options.Duration) {
+		return &ValidationError{
+			Field:   "duration",
+			Value:   fmt.Sprintf("%d", options.Duration),
+			Allowed: joinInts(caps.Durations) + " seconds for " + model,
+		}
+	}
+
+	if size != "" && !slices.Contains(caps.Resolutions, size) {
+		return &ValidationError{
+			Field:   "resolution",
+			Value:   size,
+			Allowed: strings.Join(caps.Resolutions, ", ") + " for " + model,
+		}
+	}
+
+	if !caps.ImageInput && (options.InputImagePath != "" || options.InputImageReader != nil) {
+		return &ValidationError{
+			Field:   "input image",
+			Value:   options.InputImageName,
+			Allowed: "none, " + model + " is text-to-video only",
+		}
+	}
+
+	if !caps.EndFrame && (options.EndFrameURL != "" || options.EndFramePath != "") {
+		return &ValidationError{
+			Field:   "end frame",
+			Value:   options.EndFramePath + options.EndFrameURL,
+			Allowed: "none, " + model + " does not support end frames",
+		}
+	}
+
+	if !caps.Motion && options.MotionStrength != nil {
+		return &ValidationError{
+			Field:   "motion strength",
+			Value:   fmt.Sprintf("%d", *options.MotionStrength),
+			Allowed: "none, " + model + " does not support motion control",
+		}
+	}
+
+	if !caps.Loop && options.Loop != nil && *options.Loop {
+		return &ValidationError{
+			Field:   "loop",
+			Value:   "true",
+			Allowed: "false, " + model + " does not support looping",
+		}
+	}
+
+	return nil
+}
+
+// sameOrientation reports whether a "<width>x<height>" size and a "w:h"
+// aspect ratio are both landscape, both portrait or both square. Values that
+// don't parse are left to other checks.
+func sameOrientation(size, ratio string) bool {
+	var width, height, rw, rh int
+	if _, err := fmt.Sscanf(size, "%dx%d", &width, &height); err != nil {
+		return true
+	}
+	if _, err := fmt.Sscanf(ratio, "%d:%d", &rw, &rh); err != nil {
+		return true
+	}
+	return sign(width-height) == sign(rw-rh)
+}
+
+func sign(n int) int {
+	switch {
+	case n > 0:
+		return 1
+	case n < 0:
+		return -1
+	}
+	return 0
+}
+
+func joinInts(values []int) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = strconv.Itoa(v)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,92 @@
+package video
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestNormalizeSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{name: "canonical", in: "1280x720", want: "1280x720"},
+		{name: "capital X", in: "1280X720", want: "1280x720"},
+		{name: "surrounding spaces", in: "  720x1280 ", want: "720x1280"},
+		{name: "inner spaces", in: "1280 x 720", want: "1280x720"},
+		{name: "multiplication sign", in: "1792×1024", want: "1792x1024"},
+		{name: "asterisk", in: "1024*1792", want: "1024x1792"},
+		{name: "zero width", in: "0x720", want: "0x720", wantErr: true},
+		{name: "missing height", in: "1280x", want: "1280x", wantErr: true},
+		{name: "negative", in: "-1280x720", want: "-1280x720", wantErr: true},
+		{name: "not a size", in: "hd", want: "hd", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeSize(tt.in); got != tt.want {
+				t.Errorf("normalizeSize(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+
+			// A model outside the capability table only gets the generic checks
+			err := validateSoraOptions(&VideoOptions{Resolution: tt.in}, "custom-model")
+			var validationErr *ValidationError
+			if tt.wantErr != errors.As(err, &validationErr) {
+				t.Errorf("validateSoraOptions(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEstimateCost(t *testing.T) {
+	client := NewOpenAISoraClient("https://api.example.com/v1", "test-key", "sora-2")
+
+	tests := []struct {
+		name    string
+		opts    []VideoOption
+		want    float64
+		wantErr bool
+	}{
+		{name: "defaults", want: 0.40},
+		{name: "variations", opts: []VideoOption{WithDuration(8), WithVariations(3)}, want: 2.40},
+		{name: "too many variations", opts: []VideoOption{WithVariations(MaxVariations + 1)}, wantErr: true},
+		{name: "unsupported duration", opts: []VideoOption{WithDuration(5)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := client.EstimateCost(tt.opts...)
+			var validationErr *ValidationError
+			if tt.wantErr != errors.As(err, &validationErr) {
+				t.Fatalf("EstimateCost() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("EstimateCost() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmptyTaskIDIsRejected(t *testing.T) {
+	// The base URL is never contacted
+	client := NewOpenAISoraClient("http://127.0.0.1:0", "test-key", "sora-2")
+	ctx := context.Background()
+
+	_, statusErr := client.GetTaskStatusContext(ctx, "")
+	_, startErr := client.StartTask(ctx, "")
+	for name, err := range map[string]error{
+		"GetTaskStatusContext": statusErr,
+		"StartTask":            startErr,
+		"CancelTask":           client.CancelTask(ctx, ""),
+		"DeleteTask":           client.DeleteTask(ctx, ""),
+	} {
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Errorf("%s(\"\") error = %v, want ValidationError", name, err)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package video
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// MaxVariations is the largest count accepted by WithVariations.
+const MaxVariations = 4
+
+// GenerateVariations is like GenerateVideoContext but returns one result per
+// candidate requested through WithVariations, in the order the provider
+// listed them. Every candidate is a task of its own with its own TaskID, to
+// be polled, downloaded or cancelled independently; nothing ties the IDs
+// together beyond their position in the returned slice, so callers that need
+// the grouping should store it themselves.
+//
+// Providers that ignore "n" answer with a single task, in which case the
+// slice has one element.
+func (c *OpenAISoraClient) GenerateVariations(ctx context.Context, imageURL, prompt string, opts ...VideoOption) ([]*VideoResult, error) {
+	if err := c.inflight.start(); err != nil {
+		return nil, err
+	}
+	defer c.inflight.done()
+
+	return c.submitAll(ctx, "generate", c.videosEndpoint(), imageURL, prompt, opts)
+}
+
+// soraTaskList is the response to a request for several variations.
+type soraTaskList struct {
+	Object string               `json:"object"`
+	Data   []OpenAISoraResponse `json:"data"`
+}
+
+// decodeVideoResults parses a generate response that holds either a single
+// task object or a list of them under "data". A ResponseMapper always yields
+// a single result.
+func (c *OpenAISoraClient) decodeVideoResults(resp *http.Response, body []byte) ([]*VideoResult, *APIError, error) {
+	if c.ResponseMapper == nil {
+		var list soraTaskList
+		if json.Unmarshal(body, &list) == nil && len(list.Data) > 0 {
+			meta := newResponseMeta(resp)
+			results := make([]*VideoResult, 0, len(list.Data))
+			for i := range list.Data {
+				videoResult := parseVideoResult(&list.Data[i])
+				videoResult.RawResponse = body
+				videoResult.ResponseMeta = meta
+				results = append(results, videoResult)
+			}
+			return results, nil, nil
+		}
+	}
+
+	videoResult, inlineErr, err := c.decodeVideoResult(resp, body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return []*VideoResult{videoResult}, inlineErr, nil
+}
@@ -0,0 +1,37 @@
+package video
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// maxWebhookBodySize caps the size of a webhook payload read by ParseWebhook.
+const maxWebhookBodySize = 1 << 20
+
+// ParseWebhook decodes a task callback sent to the URL registered with
+// WithWebhook. The payload is mapped exactly like a GetTaskStatus response.
+func ParseWebhook(r *http.Request) (*VideoResult, error) {
+	if r.Body == nil {
+		return nil, errors.New("empty webhook body")
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBodySize))
+	if err != nil {
+		return nil, fmt.Errorf("read webhook body: %w", err)
+	}
+
+	var payload OpenAISoraResponse
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("parse webhook body: %w", err)
+	}
+	if payload.ID == "" {
+		return nil, errors.New("webhook body has no task id")
+	}
+
+	result := parseVideoResult(&payload)
+	result.RawResponse = body
+	return result, nil
+}
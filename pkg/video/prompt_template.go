@@ -0,0 +1,120 @@
+package video
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ErrMissingVariable is returned when a prompt template uses a variable that
+// has no value.
+var ErrMissingVariable = errors.New("prompt template: missing variable")
+
+// templateVarPattern matches a variable name between the braces.
+var templateVarPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+
+// Template is a prompt with {{name}} placeholders, parsed once and rendered
+// with different variables, e.g. one scene template per character:
+//
+//	tmpl, err := video.ParseTemplate("{{character}} walks through {{setting}} at dusk")
+//	prompt, err := tmpl.Render(map[string]string{"character": "Lin", "setting": "the market"})
+//
+// Spaces inside the braces are ignored. Values are inserted literally, so a
+// value that itself contains "{{...}}" is not expanded again. There are no
+// conditionals, loops or escapes; use text/template for anything more.
+type Template struct {
+	text  string
+	parts []templatePart
+}
+
+// templatePart is either literal text or, when variable is set, a
+// placeholder.
+type templatePart struct {
+	literal  string
+	variable string
+}
+
+// ParseTemplate parses text, failing on an unclosed "{{" or a placeholder
+// whose name isn't letters, digits, '_', '.' or '-'.
+func ParseTemplate(text string) (*Template, error) {
+	t := &Template{text: text}
+
+	rest := text
+	for {
+		start := strings.Index(rest, "{{")
+		if start < 0 {
+			t.parts = append(t.parts, templatePart{literal: rest})
+			return t, nil
+		}
+		end := strings.Index(rest[start:], "}}")
+		if end < 0 {
+			return nil, fmt.Errorf("prompt template: unclosed {{ at offset %d", len(text)-len(rest)+start)
+		}
+
+		name := strings.TrimSpace(rest[start+2 : start+end])
+		if !templateVarPattern.MatchString(name) {
+			return nil, fmt.Errorf("prompt template: invalid variable name %q", name)
+		}
+
+		t.parts = append(t.parts, templatePart{literal: rest[:start]}, templatePart{variable: name})
+		rest = rest[start+end+2:]
+	}
+}
+
+// Variables returns the names used in the template, in order of first use.
+func (t *Template) Variables() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, part := range t.parts {
+		if part.variable != "" && !seen[part.variable] {
+			seen[part.variable] = true
+			names = append(names, part.variable)
+		}
+	}
+	return names
+}
+
+// Render substitutes vars into the template. Every variable must have an
+// entry in vars, otherwise an error wrapping ErrMissingVariable names all
+// the missing ones; an entry set to "" counts as present. Extra entries are
+// ignored.
+func (t *Template) Render(vars map[string]string) (string, error) {
+	var missing []string
+	var b strings.Builder
+	for _, part := range t.parts {
+		if part.variable == "" {
+			b.WriteString(part.literal)
+			continue
+		}
+		value, ok := vars[part.variable]
+		if !ok {
+			if !slices.Contains(missing, part.variable) {
+				missing = append(missing, part.variable)
+			}
+			continue
+		}
+		b.WriteString(value)
+	}
+
+	if len(missing) > 0 {
+		return "", fmt.Errorf("%w: %s", ErrMissingVariable, strings.Join(missing, ", "))
+	}
+	return b.String(), nil
+}
+
+// String returns the unrendered template text.
+func (t *Template) String() string {
+	return t.text
+}
+
+// RenderPrompt parses template and renders it with vars in one step, for
+// templates that are only used once. See Template for the syntax.
+func RenderPrompt(template string, vars map[string]string) (string, error) {
+	t, err := ParseTemplate(template)
+	if err != nil {
+		return "", err
+	}
+	return t.Render(vars)
+}
@@ -6,24 +6,197 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 )
 
+// VideoClient is implemented by every video provider so callers can pick one
+// at runtime, e.g. from a map[string]VideoClient keyed by provider name.
 type VideoClient interface {
 	GenerateVideo(imageURL, prompt string, opts ...VideoOption) (*VideoResult, error)
 	GetTaskStatus(taskID string) (*VideoResult, error)
 }
 
 type VideoResult struct {
-	TaskID       string
-	Status       string
-	VideoURL     string
-	ThumbnailURL string
-	Duration     int
-	Width        int
-	Height       int
-	Error        string
-	Completed    bool
+	TaskID       string      `json:"task_id"`
+	Status       VideoStatus `json:"status"`
+	RawStatus    string      `json:"raw_status,omitempty"` // status exactly as reported by the provider
+	Model        string      `json:"model,omitempty"`      // model the provider reports having used
+	VideoURL     string      `json:"video_url,omitempty"`
+	ThumbnailURL string      `json:"thumbnail_url,omitempty"`
+	PreviewURL   string      `json:"preview_url,omitempty"` // low-res preview while rendering, if the provider offers one
+	Duration     int         `json:"duration,omitempty"`
+	Width        int         `json:"width,omitempty"`
+	Height       int         `json:"height,omitempty"`
+	Error        string      `json:"error,omitempty"`
+	Completed    bool        `json:"completed"`
+	Progress     int         `json:"progress"`
+	// QueuePosition is the task's place in the provider's queue while
+	// queued, 1 being next; 0 when the provider doesn't report it.
+	QueuePosition int       `json:"queue_position,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	CompletedAt   time.Time `json:"completed_at"`
+
+	// Size, Seconds and Quality echo what the provider actually rendered,
+	// when it reports them.
+	Size    string `json:"size,omitempty"`
+	Seconds string `json:"seconds,omitempty"`
+	Quality string `json:"quality,omitempty"`
+
+	// Warnings are non-fatal notices from the provider, such as "prompt
+	// truncated" or "quality downgraded". They never make a call fail.
+	Warnings []string `json:"warnings,omitempty"`
+
+	// RawResponse is the unparsed response body, for debugging fields the
+	// client doesn't map.
+	RawResponse []byte `json:"raw_response,omitempty"`
+
+	// DryRunRequest is the request that would have been sent, set only for
+	// calls made with WithDryRun.
+	DryRunRequest *http.Request `json:"-"`
+
+	// ResponseMeta describes the HTTP response the result was parsed from.
+	// It is nil for results that didn't come from one, e.g. webhooks.
+	ResponseMeta *ResponseMeta `json:"-"`
+}
+
+// MarshalJSON encodes the result for storage between submit and poll. Zero
+// timestamps are omitted rather than written as year 1; DryRunRequest and
+// ResponseMeta describe a single HTTP exchange and are not persisted.
+func (r VideoResult) MarshalJSON() ([]byte, error) {
+	type alias VideoResult
+	return json.Marshal(struct {
+		alias
+		CreatedAt   *time.Time `json:"created_at,omitempty"`
+		CompletedAt *time.Time `json:"completed_at,omitempty"`
+	}{
+		alias:       alias(r),
+		CreatedAt:   nonZeroTime(r.CreatedAt),
+		CompletedAt: nonZeroTime(r.CompletedAt),
+	})
+}
+
+// UnmarshalJSON decodes a result written by MarshalJSON. A missing status
+// is derived from raw_status.
+func (r *VideoResult) UnmarshalJSON(data []byte) error {
+	type alias VideoResult
+	aux := struct {
+		*alias
+		CreatedAt   *time.Time `json:"created_at"`
+		CompletedAt *time.Time `json:"completed_at"`
+	}{alias: (*alias)(r)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	r.CreatedAt, r.CompletedAt = time.Time{}, time.Time{}
+	if aux.CreatedAt != nil {
+		r.CreatedAt = *aux.CreatedAt
+	}
+	if aux.CompletedAt != nil {
+		r.CompletedAt = *aux.CompletedAt
+	}
+	if r.Status == "" && r.RawStatus != "" {
+		r.Status = NormalizeStatus(r.RawStatus)
+	}
+	return nil
+}
+
+func nonZeroTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// String summarises the result for logs. Query parameters of the URLs are
+// masked with RedactURL since they often hold signed tokens.
+func (r *VideoResult) String() string {
+	if r == nil {
+		return "<nil>"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "VideoResult{TaskID: %s, Status: %s, Progress: %d", r.TaskID, r.Status, r.Progress)
+	if r.VideoURL != "" {
+		fmt.Fprintf(&b, ", VideoURL: %s", RedactURL(r.VideoURL))
+	}
+	if r.ThumbnailURL != "" {
+		fmt.Fprintf(&b, ", ThumbnailURL: %s", RedactURL(r.ThumbnailURL))
+	}
+	if r.PreviewURL != "" {
+		fmt.Fprintf(&b, ", PreviewURL: %s", RedactURL(r.PreviewURL))
+	}
+	if r.Error != "" {
+		fmt.Fprintf(&b, ", Error: %s", r.Error)
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// RedactURL masks the query parameter values and any password in rawURL,
+// keeping the parameter names so the URL stays recognisable in logs. A URL
+// that cannot be parsed loses everything after the path.
+func RedactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		if i := strings.IndexAny(rawURL, "?#"); i >= 0 {
+			return rawURL[:i]
+		}
+		return rawURL
+	}
+
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), "REDACTED")
+	}
+	if u.RawQuery != "" {
+		query := u.Query()
+		for key := range query {
+			query[key] = []string{"REDACTED"}
+		}
+		u.RawQuery = query.Encode()
+	}
+	return u.String()
+}
+
+// ResponseMeta carries response headers useful for throttling before the
+// provider starts answering 429.
+type ResponseMeta struct {
+	StatusCode int
+
+	// RetryAfter is the parsed Retry-After header, zero when absent.
+	RetryAfter time.Duration
+
+	// RateLimit holds every X-RateLimit-* header as sent, e.g.
+	// X-RateLimit-Remaining or OpenAI's X-Ratelimit-Remaining-Requests.
+	RateLimit http.Header
+}
+
+// Remaining returns the number of requests left in the current rate-limit
+// window, if the provider reported it. It is safe to call on a nil
+// ResponseMeta.
+func (m *ResponseMeta) Remaining() (int, bool) {
+	if m == nil {
+		return 0, false
+	}
+	for _, key := range []string{"X-Ratelimit-Remaining", "X-Ratelimit-Remaining-Requests"} {
+		if n, err := strconv.Atoi(m.RateLimit.Get(key)); err == nil {
+			return n, true
+		}
+	}
+	return 0, false
+}
+
+// RenderDuration returns how long the provider took to produce the video, or
+// zero when either timestamp is unknown.
+func (r *VideoResult) RenderDuration() time.Duration {
+	if r.CreatedAt.IsZero() || r.CompletedAt.IsZero() {
+		return 0
+	}
+	return r.CompletedAt.Sub(r.CreatedAt)
 }
 
 type VideoOptions struct {
@@ -32,6 +205,7 @@ type VideoOptions struct {
 	FPS                int
 	Resolution         string
 	AspectRatio        string
+	ResolutionTier     string
 	Style              string
 	MotionLevel        int
 	CameraMotion       string
@@ -39,10 +213,31 @@ type VideoOptions struct {
 	FirstFrameURL      string
 	LastFrameURL       string
 	ReferenceImageURLs []string
+	NegativePrompt     string
+	Quality            string
+	InputImagePath     string
+	InputImageReader   io.Reader
+	InputImageName     string
+	InputImageURL      string
+	InputImageURLs     []string
+	InputImagePaths    []string
+	IdempotencyKey     string
+	DryRun             bool
+	WebhookURL         string
+	Loop               *bool
+	RequestID          string
+	MotionStrength     *int
+	EndFrameURL        string
+	EndFramePath       string
+	ExtraFields        map[string]string
+	Variations         int
+	FetchAndUpload     bool
 }
 
 type VideoOption func(*VideoOptions)
 
+// WithModel overrides the client's model for a single call without changing
+// the client. Status queries are keyed by task ID and need no model.
 func WithModel(model string) VideoOption {
 	return func(o *VideoOptions) {
 		o.Model = model
@@ -67,12 +262,23 @@ func WithResolution(resolution string) VideoOption {
 	}
 }
 
+// WithAspectRatio sets the aspect ratio, e.g. "16:9", "9:16" or "1:1". The
+// sora-2 models render 16:9 and 9:16 only.
 func WithAspectRatio(ratio string) VideoOption {
 	return func(o *VideoOptions) {
 		o.AspectRatio = ratio
 	}
 }
 
+// WithResolutionTier selects the tier ("720p", "1080p") used to turn an
+// aspect ratio into a concrete size. The sora-2 models only have 720p sizes
+// in this scheme; use WithPreset for sora-2-pro's 1792x1024.
+func WithResolutionTier(tier string) VideoOption {
+	return func(o *VideoOptions) {
+		o.ResolutionTier = tier
+	}
+}
+
 func WithStyle(style string) VideoOption {
 	return func(o *VideoOptions) {
 		o.Style = style
@@ -91,6 +297,36 @@ func WithCameraMotion(motion string) VideoOption {
 	}
 }
 
+// WithEndFrameURL sets the image the clip should end on, for scripted
+// transitions between shots. Unlike WithLastFrame, which providers without
+// end frames ignore, it is rejected for models that don't support it.
+func WithEndFrameURL(url string) VideoOption {
+	return func(o *VideoOptions) {
+		o.EndFrameURL = url
+	}
+}
+
+// WithEndFrameFile is like WithEndFrameURL with the image uploaded from a
+// local file. It takes precedence over WithEndFrameURL.
+func WithEndFrameFile(path string) VideoOption {
+	return func(o *VideoOptions) {
+		o.EndFramePath = path
+	}
+}
+
+// WithMotionStrength sets how much the camera moves, from 0 for a static,
+// locked-off shot to 100 for a sweeping dolly. Unlike WithMotionLevel, which
+// providers without the setting ignore, it is rejected for models that
+// don't support it.
+func WithMotionStrength(level int) VideoOption {
+	return func(o *VideoOptions) {
+		o.MotionStrength = &level
+	}
+}
+
+// WithSeed requests a deterministic generation. Providers that don't support
+// seeds ignore it, and the same seed, prompt and image may still produce
+// slightly different output across model versions.
 func WithSeed(seed int64) VideoOption {
 	return func(o *VideoOptions) {
 		o.Seed = seed
@@ -115,6 +351,160 @@ func WithReferenceImages(urls []string) VideoOption {
 	}
 }
 
+// WithNegativePrompt describes content the video should avoid.
+func WithNegativePrompt(text string) VideoOption {
+	return func(o *VideoOptions) {
+		o.NegativePrompt = text
+	}
+}
+
+// WithQuality requests a quality level ("standard" or "hd") independently of
+// the resolution.
+func WithQuality(quality string) VideoOption {
+	return func(o *VideoOptions) {
+		o.Quality = quality
+	}
+}
+
+// WithInputImageURL sends url in the input_reference field for the
+// provider to fetch itself, in either encoding, rather than uploading the
+// image. It is the first reference image, ahead of GenerateVideo's imageURL
+// argument, which with the default multipart encoding the client downloads
+// and uploads because the OpenAI API wants a file there. The provider must
+// be able to reach url; add WithFetchAndUpload for URLs it can't, such as
+// ones on a private network. Local files set with WithInputImageFile take
+// precedence.
+func WithInputImageURL(url string) VideoOption {
+	return func(o *VideoOptions) {
+		o.InputImageURL = url
+	}
+}
+
+// WithInputImageFile uploads the bytes of the local image at path as the
+// first frame. Files take precedence over any image URLs.
+func WithInputImageFile(path string) VideoOption {
+	return func(o *VideoOptions) {
+		o.InputImagePath = path
+		o.InputImageName = filepath.Base(path)
+	}
+}
+
+// WithInputImageReader uploads the image read from r as the first frame.
+// filename is used to infer the content type.
+func WithInputImageReader(r io.Reader, filename string) VideoOption {
+	return func(o *VideoOptions) {
+		o.InputImageReader = r
+		o.InputImageName = filename
+	}
+}
+
+// WithInputImageURLs attaches several reference images, e.g. to keep a
+// character consistent across shots. They follow the imageURL argument.
+func WithInputImageURLs(urls ...string) VideoOption {
+	return func(o *VideoOptions) {
+		o.InputImageURLs = append(o.InputImageURLs, urls...)
+	}
+}
+
+// WithInputImageFiles uploads several local reference images. They follow
+// any file set with WithInputImageFile.
+func WithInputImageFiles(paths ...string) VideoOption {
+	return func(o *VideoOptions) {
+		o.InputImagePaths = append(o.InputImagePaths, paths...)
+	}
+}
+
+// WithFetchAndUpload makes the client download every image URL itself and
+// send the bytes, for URLs the provider can't reach. With multipart
+// encoding, which already uploads the imageURL argument and
+// WithInputImageURLs, it only changes WithInputImageURL's URL, which is then
+// uploaded too. With EncodingJSON all reference images and the end frame
+// are inlined as data URIs instead of sent as URLs.
+func WithFetchAndUpload() VideoOption {
+	return func(o *VideoOptions) {
+		o.FetchAndUpload = true
+	}
+}
+
+// WithIdempotencyKey sets the Idempotency-Key header sent with the generate
+// request. Providers that honour it return the original task instead of
+// creating a duplicate when the same key is submitted twice. Without it the
+// generate request is only retried when the connection could not be made.
+func WithIdempotencyKey(key string) VideoOption {
+	return func(o *VideoOptions) {
+		o.IdempotencyKey = key
+	}
+}
+
+// WithWebhook asks the provider to POST the finished task to url instead of
+// the caller polling for it. See ParseWebhook.
+func WithWebhook(url string) VideoOption {
+	return func(o *VideoOptions) {
+		o.WebhookURL = url
+	}
+}
+
+// WithDryRun builds the request without sending it. The result has TaskID
+// "dry-run" and the assembled request in DryRunRequest. Reference images
+// given by URL are still downloaded to build the body.
+func WithDryRun() VideoOption {
+	return func(o *VideoOptions) {
+		o.DryRun = true
+	}
+}
+
+// WithLoop asks for a seamlessly looping clip. Leaving it unset sends no
+// loop field at all.
+func WithLoop(enabled bool) VideoOption {
+	return func(o *VideoOptions) {
+		o.Loop = &enabled
+	}
+}
+
+// WithRequestID tags the call with a correlation ID for logs and the
+// X-Request-Id header. It is shorthand for ContextWithRequestID and only
+// affects observability.
+func WithRequestID(id string) VideoOption {
+	return func(o *VideoOptions) {
+		o.RequestID = id
+	}
+}
+
+// WithExtraField adds an arbitrary field to the generate request, as an
+// escape hatch for provider features without a dedicated option, e.g.
+// "style_preset" or "cfg_scale". Extra fields are sent as given and not
+// validated; they come after the client's own fields, so a clashing name is
+// sent twice in a multipart body and replaces the client's value in JSON.
+func WithExtraField(key, value string) VideoOption {
+	return func(o *VideoOptions) {
+		if o.ExtraFields == nil {
+			o.ExtraFields = make(map[string]string)
+		}
+		o.ExtraFields[key] = value
+	}
+}
+
+// WithExtraFields is WithExtraField for several fields at once.
+func WithExtraFields(fields map[string]string) VideoOption {
+	return func(o *VideoOptions) {
+		if o.ExtraFields == nil {
+			o.ExtraFields = make(map[string]string, len(fields))
+		}
+		for key, value := range fields {
+			o.ExtraFields[key] = value
+		}
+	}
+}
+
+// WithVariations asks for n candidate videos from the same prompt, sent as
+// the "n" field. Use GenerateVariations to get all of them; GenerateVideo
+// only returns the first. The default is a single video.
+func WithVariations(n int) VideoOption {
+	return func(o *VideoOptions) {
+		o.Variations = n
+	}
+}
+
 type RunwayClient struct {
 	BaseURL    string
 	APIKey     string
@@ -215,8 +605,9 @@ func (c *RunwayClient) GenerateVideo(imageURL, prompt string, opts ...VideoOptio
 
 	videoResult := &VideoResult{
 		TaskID:    result.ID,
-		Status:    result.Status,
-		Completed: result.Status == "succeeded",
+		Status:    NormalizeStatus(result.Status),
+		RawStatus: result.Status,
+		Completed: NormalizeStatus(result.Status) == StatusCompleted,
 	}
 
 	if result.Output.URL != "" {
@@ -253,8 +644,9 @@ func (c *RunwayClient) GetTaskStatus(taskID string) (*VideoResult, error) {
 
 	videoResult := &VideoResult{
 		TaskID:    result.ID,
-		Status:    result.Status,
-		Completed: result.Status == "succeeded",
+		Status:    NormalizeStatus(result.Status),
+		RawStatus: result.Status,
+		Completed: NormalizeStatus(result.Status) == StatusCompleted,
 	}
 
 	if result.Error != "" {
@@ -373,8 +765,9 @@ func (c *PikaClient) GenerateVideo(imageURL, prompt string, opts ...VideoOption)
 
 	videoResult := &VideoResult{
 		TaskID:    result.JobID,
-		Status:    result.Status,
-		Completed: result.Status == "completed",
+		Status:    NormalizeStatus(result.Status),
+		RawStatus: result.Status,
+		Completed: NormalizeStatus(result.Status) == StatusCompleted,
 	}
 
 	if result.Result.VideoURL != "" {
@@ -411,8 +804,9 @@ func (c *PikaClient) GetTaskStatus(taskID string) (*VideoResult, error) {
 
 	videoResult := &VideoResult{
 		TaskID:    result.JobID,
-		Status:    result.Status,
-		Completed: result.Status == "completed",
+		Status:    NormalizeStatus(result.Status),
+		RawStatus: result.Status,
+		Completed: NormalizeStatus(result.Status) == StatusCompleted,
 	}
 
 	if result.Error != "" {
@@ -0,0 +1,63 @@
+package video
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVideoResultJSONRoundTrip(t *testing.T) {
+	want := &VideoResult{
+		TaskID:       "video_123",
+		Status:       StatusCompleted,
+		RawStatus:    "succeeded",
+		Model:        "sora-2-pro",
+		VideoURL:     "https://cdn.example.com/video_123.mp4?sig=abc",
+		ThumbnailURL: "https://cdn.example.com/video_123.jpg",
+		PreviewURL:   "https://cdn.example.com/video_123_preview.mp4",
+		Duration:     8,
+		Width:        1280,
+		Height:       720,
+		Completed:    true,
+		Progress:     100,
+		CreatedAt:    time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+		CompletedAt:  time.Date(2025, 3, 1, 12, 2, 30, 500, time.UTC),
+		Size:         "1280x720",
+		Seconds:      "8",
+		Quality:      "hd",
+		RawResponse:  []byte(`{"id":"video_123"}`),
+	}
+
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	got := &VideoResult{}
+	if err := json.Unmarshal(data, got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip mismatch\n got: %#v\nwant: %#v", got, want)
+	}
+}
+
+func TestVideoResultJSONOmitsZeroTimes(t *testing.T) {
+	data, err := json.Marshal(&VideoResult{TaskID: "video_123", Status: StatusQueued})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if strings.Contains(string(data), "created_at") || strings.Contains(string(data), "completed_at") {
+		t.Errorf("Marshal() = %s, want zero timestamps omitted", data)
+	}
+
+	var got VideoResult
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !got.CreatedAt.IsZero() || !got.CompletedAt.IsZero() {
+		t.Errorf("timestamps = %v, %v, want zero", got.CreatedAt, got.CompletedAt)
+	}
+}
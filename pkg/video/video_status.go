@@ -0,0 +1,41 @@
+package video
+
+import "strings"
+
+// VideoStatus is a provider-independent task status.
+type VideoStatus string
+
+const (
+	StatusDraft      VideoStatus = "draft" // created but not started, see StartTask
+	StatusQueued     VideoStatus = "queued"
+	StatusInProgress VideoStatus = "in_progress"
+	StatusCompleted  VideoStatus = "completed"
+	StatusFailed     VideoStatus = "failed"
+	StatusCancelled  VideoStatus = "cancelled"
+)
+
+// NormalizeStatus maps the status strings used by the various providers onto
+// the VideoStatus constants. Unknown values are returned lower-cased.
+func NormalizeStatus(raw string) VideoStatus {
+	s := strings.ToLower(strings.TrimSpace(raw))
+	switch s {
+	case "draft":
+		return StatusDraft
+	case "queued", "queueing", "pending", "submitted", "waiting", "created", "throttled":
+		return StatusQueued
+	case "in_progress", "processing", "running", "generating", "preparing":
+		return StatusInProgress
+	case "completed", "succeeded", "success", "done", "finished":
+		return StatusCompleted
+	case "failed", "fail", "error":
+		return StatusFailed
+	case "cancelled", "canceled":
+		return StatusCancelled
+	}
+	return VideoStatus(s)
+}
+
+// IsTerminal reports whether no further status changes are expected.
+func (s VideoStatus) IsTerminal() bool {
+	return s == StatusCompleted || s == StatusFailed || s == StatusCancelled
+}
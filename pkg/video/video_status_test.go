@@ -0,0 +1,151 @@
+package video
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeStatus(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want VideoStatus
+	}{
+		{"SUCCEEDED", StatusCompleted},
+		{"Success", StatusCompleted},
+		{" completed ", StatusCompleted},
+		{"Queueing", StatusQueued},
+		{"THROTTLED", StatusQueued},
+		{"Processing", StatusInProgress},
+		{"Failed", StatusFailed},
+		{"CANCELED", StatusCancelled},
+		{"Paused", VideoStatus("paused")},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeStatus(tt.raw); got != tt.want {
+			t.Errorf("NormalizeStatus(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
+
+// statusCase is one provider status response and the result expected for it.
+type statusCase struct {
+	raw           string
+	wantStatus    VideoStatus
+	wantCompleted bool
+}
+
+// newStatusServer answers every request with body, formatted with the raw
+// status.
+func newStatusServer(t *testing.T, body, raw string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/files/retrieve") {
+			fmt.Fprint(w, `{"file":{"download_url":"https://cdn.example.com/video.mp4"},"base_resp":{"status_code":0}}`)
+			return
+		}
+		fmt.Fprintf(w, body, raw)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func checkStatus(t *testing.T, tt statusCase, result *VideoResult, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("GetTaskStatus() error = %v", err)
+	}
+	if result.Status != tt.wantStatus || result.Completed != tt.wantCompleted {
+		t.Errorf("GetTaskStatus() Status = %q, Completed = %v, want %q, %v",
+			result.Status, result.Completed, tt.wantStatus, tt.wantCompleted)
+	}
+	if result.RawStatus != tt.raw {
+		t.Errorf("GetTaskStatus() RawStatus = %q, want %q", result.RawStatus, tt.raw)
+	}
+}
+
+func TestRunwayStatusIgnoresCase(t *testing.T) {
+	for _, tt := range []statusCase{
+		{"SUCCEEDED", StatusCompleted, true},
+		{"Succeeded", StatusCompleted, true},
+		{"THROTTLED", StatusQueued, false},
+		{"RUNNING", StatusInProgress, false},
+		{"FAILED", StatusFailed, false},
+	} {
+		t.Run(tt.raw, func(t *testing.T) {
+			server := newStatusServer(t, `{"id":"task_1","status":%q,"output":{"url":"https://cdn.example.com/video.mp4"}}`, tt.raw)
+			result, err := NewRunwayClient(server.URL, "test-key", "gen3").GetTaskStatus("task_1")
+			checkStatus(t, tt, result, err)
+		})
+	}
+}
+
+func TestPikaStatusIgnoresCase(t *testing.T) {
+	for _, tt := range []statusCase{
+		{"COMPLETED", StatusCompleted, true},
+		{"Completed", StatusCompleted, true},
+		{"Pending", StatusQueued, false},
+		{"FAILED", StatusFailed, false},
+	} {
+		t.Run(tt.raw, func(t *testing.T) {
+			server := newStatusServer(t, `{"job_id":"job_1","status":%q,"result":{"video_url":"https://cdn.example.com/video.mp4"}}`, tt.raw)
+			result, err := NewPikaClient(server.URL, "test-key", "pika-1.0").GetTaskStatus("job_1")
+			checkStatus(t, tt, result, err)
+		})
+	}
+}
+
+func TestChatfireStatusIgnoresCase(t *testing.T) {
+	for _, tt := range []statusCase{
+		{"SUCCEEDED", StatusCompleted, true},
+		{"Completed", StatusCompleted, true},
+		{"Queued", StatusQueued, false},
+		{"FAILED", StatusFailed, false},
+	} {
+		t.Run(tt.raw, func(t *testing.T) {
+			server := newStatusServer(t, `{"id":"task_1","status":%q}`, tt.raw)
+			result, err := NewChatfireClient(server.URL, "test-key", "model", "/video/create", "/video/query").GetTaskStatus("task_1")
+			checkStatus(t, tt, result, err)
+		})
+	}
+}
+
+func TestVolcesArkStatusIgnoresCase(t *testing.T) {
+	for _, tt := range []statusCase{
+		{"SUCCEEDED", StatusCompleted, true},
+		{"Succeeded", StatusCompleted, true},
+		{"Running", StatusInProgress, false},
+		{"FAILED", StatusFailed, false},
+	} {
+		t.Run(tt.raw, func(t *testing.T) {
+			server := newStatusServer(t, `{"id":"task_1","status":%q}`, tt.raw)
+			result, err := NewVolcesArkClient(server.URL, "test-key", "model", "/tasks", "/tasks/{taskId}").GetTaskStatus("task_1")
+			checkStatus(t, tt, result, err)
+		})
+	}
+}
+
+func TestMinimaxStatusIgnoresCase(t *testing.T) {
+	for _, tt := range []statusCase{
+		{"Success", StatusCompleted, true},
+		{"SUCCESS", StatusCompleted, true},
+		{"Queueing", StatusQueued, false},
+		{"Processing", StatusInProgress, false},
+		{"Failed", StatusFailed, false},
+	} {
+		t.Run(tt.raw, func(t *testing.T) {
+			server := newStatusServer(t, `{"task_id":"task_1","status":%q,"file_id":"file_1","base_resp":{"status_code":0}}`, tt.raw)
+			result, err := NewMinimaxClient(server.URL, "test-key", "video-01").GetTaskStatus("task_1")
+			checkStatus(t, tt, result, err)
+			if tt.wantCompleted && result.VideoURL == "" {
+				t.Error("GetTaskStatus() VideoURL is empty for a completed task")
+			}
+		})
+	}
+}
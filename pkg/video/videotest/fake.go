@@ -0,0 +1,136 @@
+// Package videotest provides a scripted video.VideoClient and a
+// record/replay HTTP transport for tests that must not touch the network.
+package videotest
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/drama-generator/backend/pkg/video"
+)
+
+var _ video.VideoClient = (*FakeClient)(nil)
+
+// GenerateCall records the arguments of one GenerateVideo call.
+type GenerateCall struct {
+	ImageURL string
+	Prompt   string
+	Options  video.VideoOptions
+}
+
+// FakeClient is an in-memory video.VideoClient. GenerateVideo hands out task
+// IDs and successive GetTaskStatus calls for a task walk through Script, the
+// last entry repeating once reached. It is safe for concurrent use; set the
+// exported fields before the first call.
+type FakeClient struct {
+	// Script is the sequence of results reported for every task. TaskID is
+	// filled in on a copy of each entry. Nil means DefaultScript.
+	Script []*video.VideoResult
+
+	// GenerateErr and StatusErr, when set, are returned by every
+	// GenerateVideo or GetTaskStatus call respectively.
+	GenerateErr error
+	StatusErr   error
+
+	// Delay is slept at the start of every call.
+	Delay time.Duration
+
+	mu     sync.Mutex
+	calls  []GenerateCall
+	polls  map[string]int
+	nextID int
+}
+
+// NewFakeClient returns a FakeClient that runs every task through script,
+// or DefaultScript when none is given.
+func NewFakeClient(script ...*video.VideoResult) *FakeClient {
+	return &FakeClient{Script: script}
+}
+
+// DefaultScript reports a task as queued, then in progress and finally
+// completed with a video URL.
+func DefaultScript() []*video.VideoResult {
+	return []*video.VideoResult{
+		{Status: video.StatusQueued, RawStatus: "queued"},
+		{Status: video.StatusInProgress, RawStatus: "in_progress", Progress: 50},
+		{
+			Status:    video.StatusCompleted,
+			RawStatus: "completed",
+			Progress:  100,
+			Completed: true,
+			VideoURL:  "https://example.com/video.mp4",
+		},
+	}
+}
+
+// GenerateVideo records the call and returns a queued task.
+func (f *FakeClient) GenerateVideo(imageURL, prompt string, opts ...video.VideoOption) (*video.VideoResult, error) {
+	f.sleep()
+
+	options := video.VideoOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, GenerateCall{ImageURL: imageURL, Prompt: prompt, Options: options})
+	if f.GenerateErr != nil {
+		return nil, f.GenerateErr
+	}
+
+	f.nextID++
+	taskID := fmt.Sprintf("fake-task-%d", f.nextID)
+	if f.polls == nil {
+		f.polls = make(map[string]int)
+	}
+	f.polls[taskID] = 0
+
+	return &video.VideoResult{TaskID: taskID, Status: video.StatusQueued, RawStatus: "queued"}, nil
+}
+
+// GetTaskStatus returns the next scripted result for taskID. Unknown task
+// IDs yield video.ErrTaskNotFound.
+func (f *FakeClient) GetTaskStatus(taskID string) (*video.VideoResult, error) {
+	f.sleep()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.StatusErr != nil {
+		return nil, f.StatusErr
+	}
+
+	n, ok := f.polls[taskID]
+	if !ok {
+		return nil, video.ErrTaskNotFound
+	}
+	f.polls[taskID] = n + 1
+
+	script := f.Script
+	if len(script) == 0 {
+		script = DefaultScript()
+	}
+	if n >= len(script) {
+		n = len(script) - 1
+	}
+
+	result := *script[n]
+	result.TaskID = taskID
+	return &result, nil
+}
+
+// Calls returns the GenerateVideo calls made so far.
+func (f *FakeClient) Calls() []GenerateCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]GenerateCall(nil), f.calls...)
+}
+
+func (f *FakeClient) sleep() {
+	if f.Delay > 0 {
+		time.Sleep(f.Delay)
+	}
+}
@@ -0,0 +1,252 @@
+package videotest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"unicode/utf8"
+)
+
+// Mode selects whether a RecordingTransport talks to the real API or to its
+// cassette.
+type Mode int
+
+const (
+	// ModeRecord forwards requests to the wrapped transport and records
+	// every exchange; Save writes them to the cassette file.
+	ModeRecord Mode = iota
+
+	// ModeReplay answers requests from the cassette file without touching
+	// the network.
+	ModeReplay
+)
+
+// redactedValue replaces the Authorization header in recorded requests.
+const redactedValue = "REDACTED"
+
+// Cassette is the JSON file a RecordingTransport reads and writes.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is one recorded request with the response it got.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is the recorded part of an outgoing request.
+type RecordedRequest struct {
+	Method string       `json:"method"`
+	URL    string       `json:"url"`
+	Header http.Header  `json:"header,omitempty"`
+	Body   RecordedBody `json:"body,omitempty"`
+}
+
+// RecordedResponse is the recorded part of a response.
+type RecordedResponse struct {
+	StatusCode int          `json:"status_code"`
+	Header     http.Header  `json:"header,omitempty"`
+	Body       RecordedBody `json:"body,omitempty"`
+}
+
+// RecordedBody holds a request or response body. Text bodies are stored as
+// is so cassettes stay readable; anything else, such as uploaded images or
+// video bytes, is base64 encoded.
+type RecordedBody []byte
+
+func (b RecordedBody) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(b) {
+		return json.Marshal(map[string]string{"text": string(b)})
+	}
+	return json.Marshal(map[string]string{"base64": base64.StdEncoding.EncodeToString(b)})
+}
+
+func (b *RecordedBody) UnmarshalJSON(data []byte) error {
+	var body struct {
+		Text   string `json:"text"`
+		Base64 string `json:"base64"`
+	}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return err
+	}
+	if body.Base64 == "" {
+		*b = RecordedBody(body.Text)
+		return nil
+	}
+	decoded, err := base64.StdEncoding.DecodeString(body.Base64)
+	if err != nil {
+		return fmt.Errorf("decode body: %w", err)
+	}
+	*b = decoded
+	return nil
+}
+
+// RecordingTransport is an http.RoundTripper that records HTTP exchanges
+// with a real provider to a JSON cassette and replays them later, so tests
+// written against the live API can run in CI without an API key:
+//
+//	rt, err := videotest.NewRecordingTransport("testdata/generate.json", mode, nil)
+//	...
+//	defer rt.Save()
+//	client := video.NewOpenAISoraClient(baseURL, apiKey, "sora-2",
+//		video.WithTransport(rt))
+//
+// The Authorization header is never written to the cassette. Other
+// credentials, e.g. in custom headers or query strings, are recorded as is.
+//
+// During replay, each request is answered by the first unused interaction
+// with the same method and URL, so repeated polls of one task get their
+// recorded responses in order. Request bodies are recorded but not matched.
+type RecordingTransport struct {
+	path      string
+	mode      Mode
+	transport http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewRecordingTransport returns a transport recording to or replaying from
+// the cassette at path. In ModeRecord requests go through transport, or
+// http.DefaultTransport when it is nil; in ModeReplay the cassette must
+// exist.
+func NewRecordingTransport(path string, mode Mode, transport http.RoundTripper) (*RecordingTransport, error) {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	rt := &RecordingTransport{path: path, mode: mode, transport: transport}
+
+	if mode == ModeReplay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read cassette: %w", err)
+		}
+		var cassette Cassette
+		if err := json.Unmarshal(data, &cassette); err != nil {
+			return nil, fmt.Errorf("parse cassette %s: %w", path, err)
+		}
+		rt.interactions = cassette.Interactions
+		rt.used = make([]bool, len(cassette.Interactions))
+	}
+
+	return rt, nil
+}
+
+// RoundTrip records or replays req depending on the transport's Mode.
+func (rt *RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if rt.mode == ModeReplay {
+		return rt.replay(req)
+	}
+	return rt.record(req)
+}
+
+func (rt *RecordingTransport) record(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("read request body: %w", err)
+		}
+		// RoundTrip must not modify the caller's request
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+		req.ContentLength = int64(len(reqBody))
+	}
+
+	resp, err := rt.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	header := req.Header.Clone()
+	if header.Get("Authorization") != "" {
+		header.Set("Authorization", redactedValue)
+	}
+
+	rt.mu.Lock()
+	rt.interactions = append(rt.interactions, Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: header,
+			Body:   reqBody,
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header.Clone(),
+			Body:       respBody,
+		},
+	})
+	rt.mu.Unlock()
+
+	return resp, nil
+}
+
+func (rt *RecordingTransport) replay(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	url := req.URL.String()
+	for i, interaction := range rt.interactions {
+		if rt.used[i] || interaction.Request.Method != req.Method || interaction.Request.URL != url {
+			continue
+		}
+		rt.used[i] = true
+
+		recorded := interaction.Response
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+			StatusCode:    recorded.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        recorded.Header.Clone(),
+			Body:          io.NopCloser(bytes.NewReader(recorded.Body)),
+			ContentLength: int64(len(recorded.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("videotest: no recorded interaction left for %s %s", req.Method, url)
+}
+
+// Save writes the recorded interactions to the cassette file. It does
+// nothing in ModeReplay.
+func (rt *RecordingTransport) Save() error {
+	if rt.mode == ModeReplay {
+		return nil
+	}
+
+	rt.mu.Lock()
+	cassette := Cassette{Interactions: rt.interactions}
+	data, err := json.MarshalIndent(cassette, "", "  ")
+	rt.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("encode cassette: %w", err)
+	}
+
+	if err := os.WriteFile(rt.path, data, 0o644); err != nil {
+		return fmt.Errorf("write cassette: %w", err)
+	}
+	return nil
+}
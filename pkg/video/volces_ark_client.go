@@ -216,8 +216,9 @@ func (c *VolcesArkClient) GenerateVideo(imageURL, prompt string, opts ...VideoOp
 
 	videoResult := &VideoResult{
 		TaskID:    result.ID,
-		Status:    result.Status,
-		Completed: result.Status == "completed" || result.Status == "succeeded",
+		Status:    NormalizeStatus(result.Status),
+		RawStatus: result.Status,
+		Completed: NormalizeStatus(result.Status) == StatusCompleted,
 		Duration:  result.Duration,
 	}
 
@@ -272,8 +273,9 @@ func (c *VolcesArkClient) GetTaskStatus(taskID string) (*VideoResult, error) {
 
 	videoResult := &VideoResult{
 		TaskID:    result.ID,
-		Status:    result.Status,
-		Completed: result.Status == "completed" || result.Status == "succeeded",
+		Status:    NormalizeStatus(result.Status),
+		RawStatus: result.Status,
+		Completed: NormalizeStatus(result.Status) == StatusCompleted,
 		Duration:  result.Duration,
 	}
 